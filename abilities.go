@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// hiddenAbilityChance is the one in n chance a caught pokemon has its hidden ability.
+const hiddenAbilityChance = 20
+
+// staticChance is the percentage of contact moves that static paralyses the attacker with.
+const staticChance = 30
+
+type PokemonAbility struct {
+	IsHidden bool             `json:"is_hidden"`
+	Slot     int              `json:"slot"`
+	Ability  NamedAPIResource `json:"ability"`
+}
+
+// abilityHooks are the points in a battle where an ability takes effect, hooks that are not set do nothing.
+type abilityHooks struct {
+	// switchIn runs when the pokemon enters the battle
+	switchIn func(b *battle, self *battler)
+	// immuneTo reports whether moves of a type can't affect the pokemon
+	immuneTo func(moveType string) bool
+	// attackPercent scales the attacking stat the pokemon uses for a move
+	attackPercent func(self PokemonInformation, move Move) int
+	// afterHit runs after the pokemon was hit by a move
+	afterHit func(b *battle, self, attacker *battler, move Move)
+}
+
+var abilities = map[string]abilityHooks{
+	"levitate": {
+		immuneTo: func(moveType string) bool {
+			return moveType == "ground"
+		},
+	},
+	"intimidate": {
+		switchIn: func(b *battle, self *battler) {
+			for _, opponent := range b.other(b.sideOf(self)).fighters() {
+				fmt.Printf("%s's %s cuts %s's attack!\n", yellow(self.pokemon.Name), cyan("intimidate"), yellow(opponent.pokemon.Name))
+				changeStage(opponent, "attack", -1)
+			}
+		},
+	},
+	"static": {
+		afterHit: func(b *battle, self, attacker *battler, move Move) {
+			// PokeAPI has no contact flag, physical moves are treated as contact moves
+			if move.DamageClass.Name != "physical" || attacker.status != "" || attacker.fainted() || battleRand.Intn(100) >= staticChance {
+				return
+			}
+			attacker.status = "paralysis"
+			fmt.Printf("%s's %s paralyzed %s!\n", yellow(self.pokemon.Name), cyan("static"), yellow(attacker.pokemon.Name))
+		},
+	},
+	"blaze":    pinchAbility("fire"),
+	"torrent":  pinchAbility("water"),
+	"overgrow": pinchAbility("grass"),
+	"swarm":    pinchAbility("bug"),
+}
+
+// pinchAbility powers up moves of one type by half while the pokemon has a third of its hp or less.
+func pinchAbility(moveType string) abilityHooks {
+	return abilityHooks{
+		attackPercent: func(self PokemonInformation, move Move) int {
+			if move.Type.Name == moveType && self.Hp*3 <= self.MaxHp {
+				return 150
+			}
+			return 100
+		},
+	}
+}
+
+// chooseAbility picks one of the regular abilities, the hidden ability only turns up rarely.
+func chooseAbility(pokemon PokemonInformation) string {
+	regular, hidden := []string{}, []string{}
+	for _, a := range pokemon.Abilities {
+		if a.IsHidden {
+			hidden = append(hidden, a.Ability.Name)
+		} else {
+			regular = append(regular, a.Ability.Name)
+		}
+	}
+	if len(hidden) > 0 && (len(regular) == 0 || rand.Intn(hiddenAbilityChance) == 0) {
+		return hidden[rand.Intn(len(hidden))]
+	}
+	if len(regular) == 0 {
+		return ""
+	}
+	return regular[rand.Intn(len(regular))]
+}
+
+// assignMissingAbilities gives pokemon caught before abilities existed one of their abilities.
+func assignMissingAbilities() {
+	for key, pokemon := range PokeDex {
+		if pokemon.Ability == "" && len(pokemon.Abilities) > 0 {
+			pokemon.Ability = chooseAbility(pokemon)
+			PokeDex[key] = pokemon
+		}
+	}
+}
+
+func hiddenAbility(pokemon PokemonInformation) bool {
+	for _, a := range pokemon.Abilities {
+		if a.Ability.Name == pokemon.Ability {
+			return a.IsHidden
+		}
+	}
+	return false
+}
+
+func abilityImmune(pokemon PokemonInformation, moveType string) bool {
+	hooks := abilities[pokemon.Ability]
+	return hooks.immuneTo != nil && hooks.immuneTo(moveType)
+}
+
+func abilityAttackPercent(pokemon PokemonInformation, move Move) int {
+	hooks := abilities[pokemon.Ability]
+	if hooks.attackPercent == nil {
+		return 100
+	}
+	return hooks.attackPercent(pokemon, move)
+}
+
+func (b *battle) abilitySwitchIn(self *battler) {
+	if hooks := abilities[self.pokemon.Ability]; hooks.switchIn != nil {
+		hooks.switchIn(b, self)
+	}
+}
+
+func (b *battle) abilityAfterHit(self, attacker *battler, move Move) {
+	if hooks := abilities[self.pokemon.Ability]; hooks.afterHit != nil && !self.fainted() {
+		hooks.afterHit(b, self, attacker, move)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+)
+
+func withAbility(pokemon PokemonInformation, ability string) PokemonInformation {
+	pokemon.Ability = ability
+	return pokemon
+}
+
+func TestAbilityDamage(t *testing.T) {
+	attacker := testPokemon(50, 100, 100, "normal")
+	attacker.MaxHp = 150
+	defender := testPokemon(50, 100, 100, "normal")
+	cases := []struct {
+		name     string
+		attacker PokemonInformation
+		defender PokemonInformation
+		move     Move
+		expected int
+	}{
+		{name: "levitate dodges ground", attacker: attacker, defender: withAbility(defender, "levitate"), move: testMove("earthquake", "ground", "physical", 100), expected: 0},
+		{name: "levitate takes other types", attacker: attacker, defender: withAbility(defender, "levitate"), move: testMove("ember", "fire", "physical", 80), expected: 37},
+		{name: "blaze at full hp", attacker: withAbility(withHp(attacker, 150), "blaze"), defender: defender, move: testMove("ember", "fire", "physical", 80), expected: 37},
+		{name: "blaze in a pinch", attacker: withAbility(withHp(attacker, 50), "blaze"), defender: defender, move: testMove("ember", "fire", "physical", 80), expected: 54},
+		{name: "blaze ignores other types", attacker: withAbility(withHp(attacker, 50), "blaze"), defender: defender, move: testMove("waterfall", "water", "physical", 80), expected: 37},
+		{name: "torrent in a pinch", attacker: withAbility(withHp(attacker, 50), "torrent"), defender: defender, move: testMove("waterfall", "water", "physical", 80), expected: 54},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := calculateDamage(c.attacker, c.defender, c.move, damageRoll{random: 100})
+			if actual != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, actual)
+			}
+		})
+	}
+}
+
+func withHp(pokemon PokemonInformation, hp int) PokemonInformation {
+	pokemon.Hp = hp
+	return pokemon
+}
+
+func TestIntimidateOnSwitchIn(t *testing.T) {
+	gyarados := withAbility(testPokemon(50, 100, 100, "water"), "intimidate")
+	gyarados.Hp = 100
+	b := &battle{
+		player:   &battleSide{members: []*battler{{pokemon: gyarados}}},
+		opponent: &battleSide{members: []*battler{{pokemon: withHp(testPokemon(50, 100, 100), 100)}, {pokemon: withHp(testPokemon(50, 100, 100), 100)}}},
+	}
+	b.player.sendOut(1)
+	b.opponent.sendOut(2)
+	b.abilitySwitchIn(b.player.current())
+	for _, opponent := range b.opponent.members {
+		if opponent.stages["attack"] != -1 {
+			t.Errorf("expected attack to drop one stage, got %d", opponent.stages["attack"])
+		}
+	}
+	if b.player.current().stages["attack"] != 0 {
+		t.Error("intimidate should not lower its own attack")
+	}
+}
+
+func TestChooseAbilityOnlyHidden(t *testing.T) {
+	pokemon := PokemonInformation{Abilities: []PokemonAbility{{IsHidden: true, Ability: NamedAPIResource{Name: "chlorophyll"}}}}
+	if ability := chooseAbility(pokemon); ability != "chlorophyll" {
+		t.Errorf("expected the hidden ability when it is the only one, got %s", ability)
+	}
+	pokemon.Abilities = append(pokemon.Abilities, PokemonAbility{Ability: NamedAPIResource{Name: "overgrow"}})
+	seen := map[string]bool{}
+	for range 1000 {
+		seen[chooseAbility(pokemon)] = true
+	}
+	if !seen["overgrow"] {
+		t.Error("expected the regular ability to be chosen")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Difficulty picks the strategy opponents use to choose their moves.
+var Difficulty = "normal"
+
+// opponentAI chooses the move the computer plays with self against target.
+type opponentAI interface {
+	chooseMove(self, target PokemonInformation) Move
+}
+
+type randomAI struct{}
+
+// greedyAI plays the move with the highest expected damage this turn.
+type greedyAI struct{}
+
+// minimaxAI looks depth turns ahead assuming the player answers with their best move.
+type minimaxAI struct {
+	depth int
+}
+
+var difficulties = map[string]opponentAI{
+	"easy":   randomAI{},
+	"normal": greedyAI{},
+	"hard":   minimaxAI{depth: 2},
+}
+
+func aiForDifficulty(difficulty string) opponentAI {
+	ai, ok := difficulties[difficulty]
+	if !ok {
+		return randomAI{}
+	}
+	return ai
+}
+
+// movesOf returns the known moves sorted by name so strategies are deterministic.
+func movesOf(pokemon PokemonInformation) []Move {
+	moves := make([]Move, 0, len(pokemon.Moves))
+	for _, move := range pokemon.Moves {
+		moves = append(moves, move)
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i].Name < moves[j].Name
+	})
+	return moves
+}
+
+// expectedDamage averages the damage over every random roll, the crit chance and the move's accuracy.
+func expectedDamage(attacker, defender PokemonInformation, move Move) float64 {
+	critChance := map[int]float64{0: 1.0 / 24, 1: 1.0 / 8, 2: 0.5}[move.Meta.CritRate]
+	if move.Meta.CritRate >= 3 {
+		critChance = 1
+	}
+	total := 0.0
+	for random := 85; random <= 100; random++ {
+		normal := calculateDamage(attacker, defender, move, damageRoll{random: random})
+		crit := calculateDamage(attacker, defender, move, damageRoll{crit: true, random: random})
+		total += (1-critChance)*float64(normal) + critChance*float64(crit)
+	}
+	hitChance := 1.0
+	if move.Accuracy > 0 {
+		hitChance = float64(move.Accuracy) / 100
+	}
+	return total / 16 * hitChance
+}
+
+func (randomAI) chooseMove(self, _ PokemonInformation) Move {
+	moves := movesOf(self)
+	if len(moves) == 0 {
+		return Move{}
+	}
+	return moves[battleRand.Intn(len(moves))]
+}
+
+func (greedyAI) chooseMove(self, target PokemonInformation) Move {
+	best, bestDamage := Move{}, -1.0
+	for _, move := range movesOf(self) {
+		if damage := expectedDamage(self, target, move); damage > bestDamage {
+			best, bestDamage = move, damage
+		}
+	}
+	return best
+}
+
+// simulateTurn applies both moves with their expected damage, the faster pokemon moves first.
+func simulateTurn(self, target PokemonInformation, selfMove, targetMove Move) (PokemonInformation, PokemonInformation) {
+	selfHit := func() {
+		target.Hp -= int(math.Round(expectedDamage(self, target, selfMove)))
+	}
+	targetHit := func() {
+		if targetMove.Name != "" {
+			self.Hp -= int(math.Round(expectedDamage(target, self, targetMove)))
+		}
+	}
+	if self.Speed >= target.Speed {
+		selfHit()
+		if target.Hp > 0 {
+			targetHit()
+		}
+	} else {
+		targetHit()
+		if self.Hp > 0 {
+			selfHit()
+		}
+	}
+	return self, target
+}
+
+func hpFraction(pokemon PokemonInformation) float64 {
+	if pokemon.MaxHp <= 0 || pokemon.Hp <= 0 {
+		return 0
+	}
+	return float64(pokemon.Hp) / float64(pokemon.MaxHp)
+}
+
+// value scores a position for self by the difference in remaining health after depth more turns.
+func (ai minimaxAI) value(self, target PokemonInformation, depth int) float64 {
+	if depth == 0 || self.Hp <= 0 || target.Hp <= 0 {
+		return hpFraction(self) - hpFraction(target)
+	}
+	best := math.Inf(-1)
+	for _, move := range movesOf(self) {
+		best = math.Max(best, ai.worstReply(self, target, move, depth))
+	}
+	if math.IsInf(best, -1) {
+		return hpFraction(self) - hpFraction(target)
+	}
+	return best
+}
+
+// worstReply returns the score after the player answers move with the reply that hurts self the most.
+func (ai minimaxAI) worstReply(self, target PokemonInformation, move Move, depth int) float64 {
+	replies := movesOf(target)
+	if len(replies) == 0 {
+		replies = []Move{{}}
+	}
+	worst := math.Inf(1)
+	for _, reply := range replies {
+		nextSelf, nextTarget := simulateTurn(self, target, move, reply)
+		worst = math.Min(worst, ai.value(nextSelf, nextTarget, depth-1))
+	}
+	return worst
+}
+
+func (ai minimaxAI) chooseMove(self, target PokemonInformation) Move {
+	best, bestScore := Move{}, math.Inf(-1)
+	for _, move := range movesOf(self) {
+		if score := ai.worstReply(self, target, move, ai.depth); score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	return best
+}
+
+func commandDifficulty(_ *Config, difficulty string) error {
+	if difficulty == "" {
+		fmt.Printf("Current difficulty: %s\nChoose between easy, normal and hard\n", yellow(Difficulty))
+		return nil
+	}
+	if _, ok := difficulties[difficulty]; !ok {
+		return fmt.Errorf("unknown difficulty %s, choose between easy, normal and hard", difficulty)
+	}
+	Difficulty = difficulty
+	fmt.Printf("Difficulty set to %s\n", yellow(Difficulty))
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+)
+
+func withMoves(pokemon PokemonInformation, hp int, moves ...Move) PokemonInformation {
+	pokemon.MaxHp = hp
+	pokemon.Hp = hp
+	pokemon.Moves = make(map[string]Move)
+	for _, move := range moves {
+		pokemon.Moves[move.Name] = move
+	}
+	return pokemon
+}
+
+func accurate(move Move, accuracy int) Move {
+	move.Accuracy = accuracy
+	return move
+}
+
+func TestOpponentAIChoosesKnownMove(t *testing.T) {
+	self := withMoves(testPokemon(20, 50, 50, "water"), 60, testMove("water-gun", "water", "special", 40), testMove("tackle", "normal", "physical", 40))
+	target := withMoves(testPokemon(20, 50, 50, "fire"), 60, testMove("ember", "fire", "special", 40))
+	for name, ai := range difficulties {
+		move := ai.chooseMove(self, target)
+		if _, ok := self.Moves[move.Name]; !ok {
+			t.Errorf("%s: chose unknown move %q", name, move.Name)
+		}
+	}
+}
+
+func TestGreedyAI(t *testing.T) {
+	cases := []struct {
+		name     string
+		self     PokemonInformation
+		target   PokemonInformation
+		expected string
+	}{
+		{
+			name:     "prefers super effective",
+			self:     withMoves(testPokemon(20, 50, 50, "normal"), 60, testMove("water-gun", "water", "special", 40), testMove("tackle", "normal", "physical", 40)),
+			target:   withMoves(testPokemon(20, 50, 50, "fire"), 60),
+			expected: "water-gun",
+		},
+		{
+			name:     "avoids immune target",
+			self:     withMoves(testPokemon(20, 50, 50, "normal"), 60, testMove("tackle", "normal", "physical", 90), testMove("bite", "dark", "physical", 40)),
+			target:   withMoves(testPokemon(20, 50, 50, "ghost"), 60),
+			expected: "bite",
+		},
+		{
+			name:     "weighs accuracy",
+			self:     withMoves(testPokemon(20, 50, 50, "normal"), 60, accurate(testMove("mega-kick", "normal", "physical", 120), 30), accurate(testMove("headbutt", "normal", "physical", 70), 100)),
+			target:   withMoves(testPokemon(20, 50, 50, "fighting"), 60),
+			expected: "headbutt",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			move := greedyAI{}.chooseMove(c.self, c.target)
+			if move.Name != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, move.Name)
+			}
+		})
+	}
+}
+
+func TestMinimaxAITakesKnockOut(t *testing.T) {
+	self := withMoves(testPokemon(30, 60, 60, "fire"), 20, testMove("ember", "fire", "special", 40), testMove("scratch", "normal", "physical", 40))
+	target := withMoves(testPokemon(30, 60, 60, "grass"), 10, testMove("vine-whip", "grass", "physical", 45))
+	target.MaxHp = 80
+	move := minimaxAI{depth: 2}.chooseMove(self, target)
+	if move.Name != "ember" {
+		t.Errorf("expected ember to knock out the target, got %s", move.Name)
+	}
+}
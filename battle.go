@@ -0,0 +1,629 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// battler is a pokemon taking part in a battle, key is its Pokedex key and stays empty for opponents.
+type battler struct {
+	key     string
+	pokemon PokemonInformation
+	stages  map[string]int
+	status  string
+}
+
+func (b *battler) fainted() bool {
+	return b.pokemon.Hp <= 0
+}
+
+type battleSide struct {
+	name    string
+	members []*battler
+	// active holds the member index of every battle slot, one slot in singles and two in doubles
+	active []int
+}
+
+// current is the pokemon in the first slot, the only one in a single battle.
+func (s *battleSide) current() *battler {
+	return s.members[s.active[0]]
+}
+
+func (s *battleSide) inSlot(slot int) *battler {
+	return s.members[s.active[slot]]
+}
+
+// slotOf returns the slot the pokemon fights in, or -1 when it is not in battle.
+func (s *battleSide) slotOf(b *battler) int {
+	for slot, index := range s.active {
+		if s.members[index] == b {
+			return slot
+		}
+	}
+	return -1
+}
+
+func (s *battleSide) inBattle(index int) bool {
+	for _, active := range s.active {
+		if active == index {
+			return true
+		}
+	}
+	return false
+}
+
+// onField returns the pokemon in the battle slots, fainted or not.
+func (s *battleSide) onField() []*battler {
+	field := []*battler{}
+	for _, index := range s.active {
+		field = append(field, s.members[index])
+	}
+	return field
+}
+
+// fighters returns the pokemon in the battle slots that can still fight.
+func (s *battleSide) fighters() []*battler {
+	fighters := []*battler{}
+	for _, b := range s.onField() {
+		if !b.fainted() {
+			fighters = append(fighters, b)
+		}
+	}
+	return fighters
+}
+
+// sendOut fills the battle slots with the first pokemon that can fight.
+func (s *battleSide) sendOut(slots int) {
+	s.active = nil
+	for i, member := range s.members {
+		if len(s.active) < slots && !member.fainted() {
+			s.active = append(s.active, i)
+		}
+	}
+	if len(s.active) == 0 {
+		s.active = []int{0}
+	}
+}
+
+func (s *battleSide) defeated() bool {
+	for _, member := range s.members {
+		if !member.fainted() {
+			return false
+		}
+	}
+	return true
+}
+
+// nextAvailable returns the index of the first pokemon that can still fight and is not in battle, or -1.
+func (s *battleSide) nextAvailable() int {
+	for i, member := range s.members {
+		if !member.fainted() && !s.inBattle(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// battleAction is what a pokemon does on its turn: use move, use item from the bag or throw ball when they are set,
+// or switch to switchTo when it is not -1.
+// target is the opponent a single target move aims at, nil lets the battle pick one, the pokemon an item is used on
+// or the wild pokemon a ball is thrown at.
+type battleAction struct {
+	side     *battleSide
+	actor    *battler
+	move     Move
+	item     string
+	ball     string
+	target   *battler
+	switchTo int
+}
+
+type battle struct {
+	player   *battleSide
+	opponent *battleSide
+	trainer  *Trainer
+	// friendly battles like PvP give no experience
+	friendly       bool
+	playerSource   actionSource
+	opponentSource actionSource
+	log            *battleLog
+	onEvent        func(battleEvent)
+	turn           int
+	// slots is the number of pokemon each side has in battle, 2 in double battles
+	slots int
+	field fieldState
+	// caught is the wild pokemon the player caught, it ends the battle
+	caught *battler
+	// levelled holds the Pokedex keys of party pokemon that gained a level
+	levelled map[string]bool
+}
+
+func newPlayerSide() *battleSide {
+	side := &battleSide{name: "You"}
+	for _, key := range Party {
+		pokemon, ok := PokeDex[key]
+		if !ok {
+			continue
+		}
+		resetStats(&pokemon)
+		side.members = append(side.members, &battler{key: key, pokemon: pokemon})
+	}
+	return side
+}
+
+// writeBack stores what the party learned during the battle in the Pokedex and heals it.
+func (s *battleSide) writeBack() {
+	for _, member := range s.members {
+		if member.key == "" {
+			continue
+		}
+		resetStats(&member.pokemon)
+		PokeDex[member.key] = member.pokemon
+	}
+}
+
+func (b *battle) other(side *battleSide) *battleSide {
+	if side == b.player {
+		return b.opponent
+	}
+	return b.player
+}
+
+func printMoves(pokemon PokemonInformation) {
+	for move := range pokemon.Moves {
+		fmt.Printf("%s type: %s\n", cyan(pokemon.Moves[move].Name), green(pokemon.Moves[move].Type.Name))
+	}
+}
+
+// actionSource decides what the pokemon in a slot does each turn and which pokemon replaces a fainted one.
+type actionSource interface {
+	chooseAction(b *battle, side *battleSide, slot int) (battleAction, error)
+	chooseReplacement(b *battle, side *battleSide, slot int) (int, error)
+}
+
+// consolePlayer reads the player's choices from the terminal.
+type consolePlayer struct {
+	scanner *bufio.Scanner
+}
+
+// computerOpponent plays with the opponent AI picked by the difficulty setting.
+type computerOpponent struct{}
+
+func (c consolePlayer) chooseAction(b *battle, side *battleSide, slot int) (battleAction, error) {
+	active := side.inSlot(slot)
+	if field := b.field.describe(); field != "" && slot == 0 {
+		fmt.Println(cyan(field))
+	}
+	if len(side.active) > 1 {
+		fmt.Printf("What will %s do?\n", yellow(active.pokemon.Name))
+	}
+	if len(active.pokemon.Moves) == 0 {
+		fmt.Printf("%s knows no moves yet, teach it one with the %s command\n", yellow(active.pokemon.Name), blue("learnmove"))
+	}
+	printMoves(active.pokemon)
+	fmt.Print(b.actionPrompt())
+	for c.scanner.Scan() {
+		input := strings.TrimSpace(strings.ToLower(c.scanner.Text()))
+		if name, ok := strings.CutPrefix(input, "switch "); ok {
+			if index := switchIndex(side, strings.TrimSpace(name)); index >= 0 {
+				return battleAction{side: side, actor: active, switchTo: index}, nil
+			}
+		} else if args, ok := strings.CutPrefix(input, "use "); ok {
+			if b.friendly {
+				fmt.Println("Items can't be used in PvP battles")
+			} else if action, ok := battleItem(side, active, args); ok {
+				return action, nil
+			}
+		} else if ball, ok := strings.CutPrefix(input, "catch"); ok {
+			if action, ok := catchAction(b, side, active, strings.TrimSpace(ball)); ok {
+				return action, nil
+			}
+		} else if move, ok := active.pokemon.Moves[input]; ok {
+			return battleAction{side: side, actor: active, move: move, target: c.chooseTarget(b, side, move), switchTo: -1}, nil
+		}
+		printMoves(active.pokemon)
+		fmt.Print("\n" + b.actionPrompt())
+	}
+	// stdin closed, keep the battle going with the first move
+	return battleAction{side: side, actor: active, move: firstMove(active.pokemon), switchTo: -1}, nil
+}
+
+// actionPrompt lists what the player can do this turn, balls can only be thrown in wild battles.
+func (b *battle) actionPrompt() string {
+	if b.trainer != nil || b.friendly {
+		return "choose a move to play, switch <pokemon> or use <item> [pokemon]:"
+	}
+	return "choose a move to play, switch <pokemon>, use <item> [pokemon] or catch [ball]:"
+}
+
+func (c consolePlayer) chooseReplacement(_ *battle, side *battleSide, _ int) (int, error) {
+	for {
+		fmt.Printf("Choose your next pokemon:")
+		if !c.scanner.Scan() {
+			return side.nextAvailable(), nil
+		}
+		if index := switchIndex(side, strings.TrimSpace(strings.ToLower(c.scanner.Text()))); index >= 0 {
+			return index, nil
+		}
+	}
+}
+
+func (computerOpponent) chooseAction(b *battle, side *battleSide, slot int) (battleAction, error) {
+	active := side.inSlot(slot)
+	target := weakestTarget(b.other(side).fighters())
+	move := aiForDifficulty(Difficulty).chooseMove(active.pokemon, target.pokemon)
+	return battleAction{side: side, actor: active, move: move, target: target, switchTo: -1}, nil
+}
+
+func (computerOpponent) chooseReplacement(_ *battle, side *battleSide, _ int) (int, error) {
+	return side.nextAvailable(), nil
+}
+
+func firstMove(pokemon PokemonInformation) Move {
+	moves := movesOf(pokemon)
+	if len(moves) == 0 {
+		return Move{}
+	}
+	return moves[0]
+}
+
+// switchIndex validates a switch target by ID, nickname or name and returns its index or -1.
+func switchIndex(side *battleSide, name string) int {
+	i := memberIndex(side, name)
+	switch {
+	case i < 0:
+		fmt.Printf("%s is not in your party\n", yellow(name))
+	case side.inBattle(i):
+		fmt.Printf("%s is already in battle\n", yellow(name))
+	case side.members[i].fainted():
+		fmt.Printf("%s has fainted and can't battle\n", yellow(name))
+	default:
+		return i
+	}
+	return -1
+}
+
+// memberIndex finds a party member by ID, nickname or name and returns its index or -1.
+func memberIndex(side *battleSide, name string) int {
+	for i, member := range side.members {
+		if member.key == strings.TrimPrefix(name, "#") || member.pokemon.Name == name || strings.EqualFold(member.pokemon.Nickname, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// actionPriority puts switching and items ahead of every move, moves use their PokeAPI priority.
+func actionPriority(action battleAction) int {
+	if action.switchTo >= 0 || action.item != "" || action.ball != "" {
+		return 7
+	}
+	return action.move.Priority
+}
+
+// movesFirst reports whether a resolves before b: priority first, then effective speed, then tieBreak.
+func movesFirst(a, b battleAction, tieBreak func() bool) bool {
+	if pa, pb := actionPriority(a), actionPriority(b); pa != pb {
+		return pa > pb
+	}
+	if sa, sb := effectiveSpeed(a.actor), effectiveSpeed(b.actor); sa != sb {
+		return sa > sb
+	}
+	return tieBreak()
+}
+
+// order returns the actions in the order they resolve this turn, ties are broken at random.
+func (b *battle) order(actions []battleAction) []battleAction {
+	battleRand.Shuffle(len(actions), func(i, j int) {
+		actions[i], actions[j] = actions[j], actions[i]
+	})
+	sort.SliceStable(actions, func(i, j int) bool {
+		return movesFirst(actions[i], actions[j], func() bool { return false })
+	})
+	return actions
+}
+
+func (b *battle) switchTo(side *battleSide, slot, index int) {
+	leaving := side.inSlot(slot)
+	if side == b.player && !leaving.fainted() {
+		fmt.Printf("Come back %s! Go %s!\n", yellow(leaving.pokemon.Name), yellow(side.members[index].pokemon.Name))
+	} else if side == b.player {
+		fmt.Printf("Go %s!\n", yellow(side.members[index].pokemon.Name))
+	} else {
+		fmt.Printf("%s sends out %s!\n", side.name, yellow(side.members[index].pokemon.Name))
+	}
+	leaving.stages = nil
+	side.active[slot] = index
+	b.record(battleEvent{Kind: "switch", Side: b.sideName(side), Pokemon: side.inSlot(slot).pokemon.Name})
+	b.abilitySwitchIn(side.inSlot(slot))
+}
+
+func (b *battle) execute(action battleAction) {
+	slot := action.side.slotOf(action.actor)
+	if action.actor.fainted() || slot < 0 {
+		return
+	}
+	if action.switchTo >= 0 {
+		// in doubles the partner may have switched to the same pokemon already
+		if !action.side.inBattle(action.switchTo) && !action.side.members[action.switchTo].fainted() {
+			b.switchTo(action.side, slot, action.switchTo)
+		}
+		return
+	}
+	if action.item != "" {
+		b.useItem(action)
+		return
+	}
+	if action.ball != "" {
+		b.throwAt(action)
+		return
+	}
+	if !canMove(action.actor) {
+		return
+	}
+	targets := b.targets(action)
+	if len(targets) == 0 {
+		return
+	}
+	fmt.Printf("%s plays %s\n", yellow(action.actor.pokemon.Name), cyan(action.move.Name))
+	b.record(battleEvent{Kind: "move", Side: b.sideName(action.side), Pokemon: action.actor.pokemon.Name, Move: action.move.Name})
+	if b.changeField(action.move) {
+		return
+	}
+	conditions := b.field.conditions(len(targets) > 1)
+	for _, target := range targets {
+		damage, result := calculateDamageMove(effectivePokemon(action.actor), effectivePokemon(target), action.move, conditions)
+		if result == "miss" {
+			b.record(battleEvent{Kind: "miss", Side: b.sideName(action.side), Pokemon: action.actor.pokemon.Name, Move: action.move.Name})
+			continue
+		}
+		if result == "immune" {
+			b.record(battleEvent{Kind: "immune", Side: b.sideName(b.sideOf(target)), Pokemon: target.pokemon.Name, Move: action.move.Name})
+			continue
+		}
+		target.pokemon.Hp -= damage
+		if damage > 0 {
+			b.record(battleEvent{Kind: "damage", Side: b.sideName(b.sideOf(target)), Pokemon: target.pokemon.Name, Damage: damage, Hp: target.pokemon.Hp})
+		}
+		if damage > 0 {
+			b.abilityAfterHit(target, action.actor, action.move)
+		}
+		b.checkHeldItem(target)
+	}
+	b.checkHeldItem(action.actor)
+}
+
+// reward gives the winner effort values and experience for knocking out opponent.
+func (b *battle) reward(winner, opponent *battler) error {
+	oldLevel := winner.pokemon.Level
+	gainEffortValues(&winner.pokemon, opponent.pokemon)
+	experience := experienceGain(opponent.pokemon)
+	if b.trainer != nil {
+		// trainer owned pokemon give one and a half times the experience
+		experience = experience * 3 / 2
+	}
+	err := gainExperience(&winner.pokemon, experience)
+	if err != nil {
+		return err
+	}
+	if winner.pokemon.Level > oldLevel {
+		b.levelled[winner.key] = true
+	}
+	return nil
+}
+
+// handleFainted rewards the player for knocked out opponents and replaces fainted pokemon on both sides.
+func (b *battle) handleFainted() error {
+	for _, opponent := range b.opponent.onField() {
+		if !opponent.fainted() {
+			continue
+		}
+		fmt.Printf("%s %s\n", yellow(opponent.pokemon.Name), boldRed("fainted"))
+		b.record(battleEvent{Kind: "faint", Side: "opponent", Pokemon: opponent.pokemon.Name})
+		if !b.friendly {
+			for _, winner := range b.player.fighters() {
+				err := b.reward(winner, opponent)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err := b.replace(b.opponent, b.opponentSource, opponent)
+		if err != nil {
+			return err
+		}
+	}
+	for _, active := range b.player.onField() {
+		if !active.fainted() {
+			continue
+		}
+		fmt.Printf("%s %s\n", yellow(active.pokemon.Name), boldRed("fainted"))
+		b.record(battleEvent{Kind: "faint", Side: "player", Pokemon: active.pokemon.Name})
+		err := b.replace(b.player, b.playerSource, active)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replace sends in the pokemon the side's source picks for the slot of a fainted pokemon.
+// Without pokemon left to send in a double battle goes on with one slot less.
+func (b *battle) replace(side *battleSide, source actionSource, fainted *battler) error {
+	if side.defeated() || b.other(side).defeated() {
+		return nil
+	}
+	slot := side.slotOf(fainted)
+	if side.nextAvailable() < 0 {
+		side.active = append(side.active[:slot], side.active[slot+1:]...)
+		return nil
+	}
+	index, err := source.chooseReplacement(b, side, slot)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(side.members) || side.members[index].fainted() || side.inBattle(index) {
+		index = side.nextAvailable()
+	}
+	b.switchTo(side, slot, index)
+	return nil
+}
+
+func (b *battle) sourceOf(side *battleSide) actionSource {
+	if side == b.player {
+		return b.playerSource
+	}
+	return b.opponentSource
+}
+
+// run plays turns until one side has no pokemon left and reports whether the player won.
+func (b *battle) run() (bool, error) {
+	slots := max(b.slots, 1)
+	b.player.sendOut(slots)
+	b.opponent.sendOut(slots)
+	for _, active := range b.player.fighters() {
+		fmt.Printf("Go %s!\n", yellow(active.pokemon.Name))
+	}
+	for _, active := range append(b.player.fighters(), b.opponent.fighters()...) {
+		b.abilitySwitchIn(active)
+	}
+	for !b.player.defeated() && !b.opponent.defeated() && b.caught == nil {
+		b.turn++
+		actions := []battleAction{}
+		for _, side := range []*battleSide{b.player, b.opponent} {
+			for slot := range side.active {
+				action, err := b.sourceOf(side).chooseAction(b, side, slot)
+				if err != nil {
+					return false, err
+				}
+				actions = append(actions, action)
+			}
+		}
+		for _, action := range b.order(actions) {
+			b.execute(action)
+			err := b.handleFainted()
+			if err != nil {
+				return false, err
+			}
+			if b.player.defeated() || b.opponent.defeated() || b.caught != nil {
+				break
+			}
+		}
+		if !b.player.defeated() && !b.opponent.defeated() && b.caught == nil {
+			b.endOfTurn()
+			err := b.handleFainted()
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+	won := b.opponent.defeated()
+	outcome := "lost"
+	if won {
+		outcome = "won"
+	} else if b.caught != nil {
+		outcome = "caught"
+	}
+	b.record(battleEvent{Kind: "outcome", Outcome: outcome})
+	return won, nil
+}
+
+// runBattle lets the party fight the opponent side and handles what happens after the battle.
+func runBattle(opponent *battleSide, trainer *Trainer, slots int) (bool, error) {
+	b := &battle{
+		slots:          slots,
+		player:         newPlayerSide(),
+		opponent:       opponent,
+		trainer:        trainer,
+		playerSource:   consolePlayer{scanner: bufio.NewScanner(os.Stdin)},
+		opponentSource: computerOpponent{},
+		levelled:       make(map[string]bool),
+	}
+	if len(b.player.members) == 0 {
+		fmt.Println("None of your party pokemon are in your Pokedex")
+		return false, nil
+	}
+	seed := time.Now().UnixNano()
+	battleRand = rand.New(rand.NewSource(seed))
+	b.log = startBattleLog(b, seed)
+	won, err := b.run()
+	b.log.close()
+	b.player.writeBack()
+	if err != nil {
+		return false, err
+	}
+	if b.caught != nil {
+		delete(catchablePokemon, b.caught.key)
+		err = keepCaught(b.caught.pokemon)
+		if err != nil {
+			return false, err
+		}
+	} else if !won {
+		fmt.Println(boldRed("Your whole party fainted!"))
+		return false, nil
+	} else {
+		fmt.Println(boldGreen("You won!"))
+	}
+	for key := range b.levelled {
+		_, err = offerEvolution(key, evolutionTrigger{kind: "level-up"})
+		if err != nil {
+			return won, err
+		}
+	}
+	return won, nil
+}
+
+// commandBattle fights a wild pokemon, with --double two wild pokemon fight two of the party at once.
+func commandBattle(_ *Config, args string) error {
+	slots := 1
+	names := []string{}
+	for _, field := range strings.Fields(args) {
+		if field == "--double" {
+			slots = 2
+		} else {
+			names = append(names, field)
+		}
+	}
+	if len(Party) < 1 {
+		fmt.Printf("You have no pokemon in your party to fight with\nGo catch some pokemon!\n")
+		return nil
+	}
+	if len(Party) < slots {
+		fmt.Println("You need at least two pokemon in your party for a double battle")
+		return nil
+	}
+	if slots == 2 && len(names) == 1 {
+		// a double battle against a single species meets two of them
+		names = append(names, names[0])
+	}
+	if len(names) == 0 {
+		names = append(names, "")
+	}
+	if len(names) != slots {
+		return fmt.Errorf("usage: battle <pokemon> or battle --double <pokemon> [pokemon]")
+	}
+	side := &battleSide{name: "wild"}
+	for _, name := range names {
+		pokemon, ok := catchablePokemon[name]
+		if !ok {
+			fmt.Println("You can't fight a pokemon you have not yet found using the find command")
+			return nil
+		}
+		resetStats(&pokemon)
+		pokemon.Ability = chooseAbility(pokemon)
+		pokemon.Moves = make(map[string]Move)
+		_, err := simpelLearnMove(&pokemon)
+		if err != nil {
+			return err
+		}
+		// the key of a wild pokemon is its name in catchablePokemon
+		side.members = append(side.members, &battler{key: name, pokemon: pokemon})
+	}
+	_, err := runBattle(side, nil, slots)
+	return err
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func speedAction(speed int, stages map[string]int, status string, priority int) battleAction {
+	actor := &battler{pokemon: PokemonInformation{Speed: speed}, stages: stages, status: status}
+	move := testMove("tackle", "normal", "physical", 40)
+	move.Priority = priority
+	return battleAction{actor: actor, move: move, switchTo: -1}
+}
+
+func TestMovesFirst(t *testing.T) {
+	switchAction := speedAction(10, nil, "", 0)
+	switchAction.switchTo = 1
+	cases := []struct {
+		name     string
+		a        battleAction
+		b        battleAction
+		tieBreak bool
+		expected bool
+	}{
+		{name: "faster goes first", a: speedAction(100, nil, "", 0), b: speedAction(50, nil, "", 0), expected: true},
+		{name: "slower goes second", a: speedAction(50, nil, "", 0), b: speedAction(100, nil, "", 0), expected: false},
+		{name: "priority beats speed", a: speedAction(10, nil, "", 1), b: speedAction(200, nil, "", 0), expected: true},
+		{name: "negative priority goes last", a: speedAction(200, nil, "", -6), b: speedAction(10, nil, "", 0), expected: false},
+		{name: "higher priority of two", a: speedAction(10, nil, "", 2), b: speedAction(200, nil, "", 1), expected: true},
+		{name: "same priority falls back to speed", a: speedAction(120, nil, "", 1), b: speedAction(100, nil, "", 1), expected: true},
+		{name: "speed stage boost", a: speedAction(60, map[string]int{"speed": 1}, "", 0), b: speedAction(80, nil, "", 0), expected: true},
+		{name: "speed stage drop", a: speedAction(100, map[string]int{"speed": -1}, "", 0), b: speedAction(80, nil, "", 0), expected: false},
+		{name: "paralysis halves speed", a: speedAction(100, nil, "paralysis", 0), b: speedAction(60, nil, "", 0), expected: false},
+		{name: "tie broken in favour", a: speedAction(100, nil, "", 0), b: speedAction(100, nil, "", 0), tieBreak: true, expected: true},
+		{name: "tie broken against", a: speedAction(100, nil, "", 0), b: speedAction(100, nil, "", 0), tieBreak: false, expected: false},
+		{name: "tie after stages", a: speedAction(100, map[string]int{"speed": -1}, "", 0), b: speedAction(200, map[string]int{"speed": -4}, "", 0), tieBreak: true, expected: true},
+		{name: "switch beats priority move", a: switchAction, b: speedAction(200, nil, "", 5), expected: true},
+		{name: "priority move loses to switch", a: speedAction(200, nil, "", 5), b: switchAction, expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := movesFirst(c.a, c.b, func() bool { return c.tieBreak })
+			if actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDoubleBattleTargets(t *testing.T) {
+	newSide := func(names ...string) *battleSide {
+		side := &battleSide{}
+		for _, name := range names {
+			side.members = append(side.members, &battler{pokemon: PokemonInformation{Name: name, Hp: 10, MaxHp: 10}})
+		}
+		side.sendOut(2)
+		return side
+	}
+	b := &battle{player: newSide("pikachu", "eevee", "mew"), opponent: newSide("zubat", "geodude")}
+	pikachu, geodude := b.player.members[0], b.opponent.members[1]
+	if b.player.inBattle(2) {
+		t.Fatal("expected only two pokemon to be sent out")
+	}
+
+	names := func(targets []*battler) []string {
+		result := []string{}
+		for _, target := range targets {
+			result = append(result, target.pokemon.Name)
+		}
+		return result
+	}
+	move := func(target string) Move {
+		m := testMove("move", "normal", "physical", 40)
+		m.Target.Name = target
+		return m
+	}
+	cases := []struct {
+		name     string
+		move     Move
+		target   *battler
+		expected []string
+	}{
+		{name: "chosen target", move: move("selected-pokemon"), target: geodude, expected: []string{"geodude"}},
+		{name: "no target picks the first opponent", move: move("selected-pokemon"), expected: []string{"zubat"}},
+		{name: "spread over opponents", move: move("all-opponents"), expected: []string{"zubat", "geodude"}},
+		{name: "spread includes the ally", move: move("all-other-pokemon"), expected: []string{"zubat", "geodude", "eevee"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := names(b.targets(battleAction{side: b.player, actor: pikachu, move: c.move, target: c.target, switchTo: -1}))
+			if strings.Join(actual, ",") != strings.Join(c.expected, ",") {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+
+	geodude.pokemon.Hp = 0
+	actual := names(b.targets(battleAction{side: b.player, actor: pikachu, move: move("selected-pokemon"), target: geodude, switchTo: -1}))
+	if len(actual) != 1 || actual[0] != "zubat" {
+		t.Errorf("expected a fainted target to be replaced by zubat, got %v", actual)
+	}
+}
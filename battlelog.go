@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var battleLogFolder = "save_folder/battles"
+
+// battleRand drives every random roll in a battle, it is reseeded per battle so the log can record the seed.
+var battleRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+type battleSnapshot struct {
+	Name   string   `json:"name"`
+	Level  int      `json:"level"`
+	MaxHp  int      `json:"max_hp"`
+	Types  []string `json:"types"`
+	Moves  []string `json:"moves"`
+	Attack int      `json:"attack"`
+	Speed  int      `json:"speed"`
+}
+
+// battleEvent is one line of a battle log, kind says which of the other fields are set.
+type battleEvent struct {
+	Kind     string           `json:"kind"` // start, move, miss, immune, damage, heal, item, throw, switch, faint, field, field-end or outcome
+	Turn     int              `json:"turn"`
+	Seed     int64            `json:"seed,omitempty"`
+	Time     string           `json:"time,omitempty"`
+	Opponent string           `json:"opponent,omitempty"`
+	Player   []battleSnapshot `json:"player,omitempty"`
+	Enemy    []battleSnapshot `json:"enemy,omitempty"`
+	Side     string           `json:"side,omitempty"`
+	Pokemon  string           `json:"pokemon,omitempty"`
+	Move     string           `json:"move,omitempty"`
+	Item     string           `json:"item,omitempty"`
+	Damage   int              `json:"damage,omitempty"`
+	Heal     int              `json:"heal,omitempty"`
+	Shakes   int              `json:"shakes,omitempty"`
+	Hp       int              `json:"hp,omitempty"`
+	Outcome  string           `json:"outcome,omitempty"`
+	Field    string           `json:"field,omitempty"`
+}
+
+type battleLog struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func snapshot(side *battleSide) []battleSnapshot {
+	snapshots := []battleSnapshot{}
+	for _, member := range side.members {
+		pokemon := member.pokemon
+		s := battleSnapshot{Name: pokemon.Name, Level: pokemon.Level, MaxHp: pokemon.MaxHp, Attack: pokemon.Attack, Speed: pokemon.Speed}
+		for _, t := range pokemon.Types {
+			s.Types = append(s.Types, t.Type.Name)
+		}
+		for _, move := range movesOf(pokemon) {
+			s.Moves = append(s.Moves, move.Name)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots
+}
+
+// startBattleLog creates the log file for a battle, a battle still runs when its log can't be written.
+func startBattleLog(b *battle, seed int64) *battleLog {
+	err := os.MkdirAll(battleLogFolder, 0755)
+	if err != nil {
+		fmt.Printf("Unable to record battle: %v\n", err)
+		return nil
+	}
+	id := time.Now().Format("20060102_150405")
+	path := filepath.Join(battleLogFolder, id+".jsonl")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(battleLogFolder, fmt.Sprintf("%s-%d.jsonl", id, i))
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("Unable to record battle: %v\n", err)
+		return nil
+	}
+	l := &battleLog{file: file, encoder: json.NewEncoder(file)}
+	l.write(battleEvent{
+		Kind:     "start",
+		Seed:     seed,
+		Time:     time.Now().Format(time.RFC3339),
+		Opponent: b.opponent.name,
+		Player:   snapshot(b.player),
+		Enemy:    snapshot(b.opponent),
+	})
+	return l
+}
+
+func (l *battleLog) write(event battleEvent) {
+	if l == nil {
+		return
+	}
+	err := l.encoder.Encode(event)
+	if err != nil {
+		fmt.Printf("Unable to record battle: %v\n", err)
+	}
+}
+
+func (l *battleLog) close() {
+	if l == nil {
+		return
+	}
+	l.file.Close()
+}
+
+// record adds an event for the current turn to the battle log.
+func (b *battle) record(event battleEvent) {
+	event.Turn = b.turn
+	b.log.write(event)
+	if b.onEvent != nil {
+		b.onEvent(event)
+	}
+}
+
+func (b *battle) sideName(side *battleSide) string {
+	if side == b.player {
+		return "player"
+	}
+	return "opponent"
+}
+
+func readBattleLog(id string) ([]battleEvent, error) {
+	// the id is typed by the player, it must not point outside the battle folder
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("invalid battle id %s, use the battles command to list them", id)
+	}
+	file, err := os.Open(filepath.Join(battleLogFolder, id+".jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("no battle with id %s, use the battles command to list them", id)
+	}
+	defer file.Close()
+	events := []battleEvent{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event battleEvent
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+func commandBattles(_ *Config, _ string) error {
+	files, err := os.ReadDir(battleLogFolder)
+	if os.IsNotExist(err) || len(files) == 0 {
+		fmt.Println("You have not fought any battles yet")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ids := []string{}
+	for _, file := range files {
+		if id, ok := strings.CutSuffix(file.Name(), ".jsonl"); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	fmt.Println(orange("Your battles:"))
+	for _, id := range ids {
+		events, err := readBattleLog(id)
+		if err != nil || len(events) == 0 {
+			continue
+		}
+		start, last := events[0], events[len(events)-1]
+		result := yellow("unfinished")
+		switch last.Outcome {
+		case "won":
+			result = boldGreen("won")
+		case "lost":
+			result = boldRed("lost")
+		case "caught":
+			result = boldGreen("caught")
+		}
+		opponents := []string{}
+		for _, s := range start.Enemy {
+			opponents = append(opponents, s.Name)
+		}
+		fmt.Printf("%s: vs %s (%s) %s\n", blue(id), start.Opponent, strings.Join(opponents, ", "), result)
+	}
+	return nil
+}
+
+func commandReplay(_ *Config, id string) error {
+	if id == "" {
+		return fmt.Errorf("usage: replay <id>, use the battles command to list them")
+	}
+	events, err := readBattleLog(id)
+	if err != nil {
+		return err
+	}
+	turn := -1
+	for _, event := range events {
+		if event.Turn != turn {
+			turn = event.Turn
+			if turn > 0 {
+				time.Sleep(500 * time.Millisecond)
+				fmt.Println(orange(fmt.Sprintf("-- turn %d --", turn)))
+			}
+		}
+		renderEvent(event)
+	}
+	return nil
+}
+
+// renderEvent prints a recorded battle event from the player's point of view.
+func renderEvent(event battleEvent) {
+	switch event.Kind {
+	case "start":
+		fmt.Printf("Battle of %s (seed %d) against %s\n", event.Time, event.Seed, event.Opponent)
+		for _, s := range event.Player {
+			fmt.Printf("%s %s level %d, moves: %s\n", blue("you:"), yellow(s.Name), s.Level, strings.Join(s.Moves, ", "))
+		}
+		for _, s := range event.Enemy {
+			fmt.Printf("%s %s level %d, moves: %s\n", red("opponent:"), yellow(s.Name), s.Level, strings.Join(s.Moves, ", "))
+		}
+	case "move":
+		fmt.Printf("%s plays %s\n", yellow(event.Pokemon), cyan(event.Move))
+	case "miss":
+		fmt.Printf("%s missed\n", cyan(event.Move))
+	case "immune":
+		fmt.Printf("%s doesn't affect %s\n", cyan(event.Move), yellow(event.Pokemon))
+	case "damage":
+		fmt.Printf("%s took %s damage, %d hp left\n", yellow(event.Pokemon), red(event.Damage), max(event.Hp, 0))
+	case "heal":
+		fmt.Printf("%s restored %s hp, %d hp left\n", yellow(event.Pokemon), green(event.Heal), event.Hp)
+	case "item":
+		fmt.Printf("%s was used on %s, %d hp left\n", cyan(event.Item), yellow(event.Pokemon), event.Hp)
+	case "throw":
+		fmt.Printf("a %s was thrown at %s and shook %d times\n", cyan(event.Item), yellow(event.Pokemon), event.Shakes)
+	case "switch":
+		fmt.Printf("%s sends out %s\n", event.Side, yellow(event.Pokemon))
+	case "field":
+		fmt.Println(cyan(fieldTexts[event.Field].start))
+	case "field-end":
+		fmt.Println(cyan(fieldTexts[event.Field].end))
+	case "faint":
+		fmt.Printf("%s %s\n", yellow(event.Pokemon), boldRed("fainted"))
+	case "outcome":
+		if event.Outcome == "won" {
+			fmt.Println(boldGreen("You won!"))
+		} else if event.Outcome == "caught" {
+			fmt.Println(boldGreen("You caught it!"))
+		} else {
+			fmt.Println(boldRed("You lost!"))
+		}
+	}
+}
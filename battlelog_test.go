@@ -0,0 +1,69 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBattleLogRoundTrip(t *testing.T) {
+	folder := battleLogFolder
+	battleLogFolder = t.TempDir()
+	defer func() { battleLogFolder = folder }()
+	battleRand.Seed(7)
+
+	newSide := func(name string, pokemon PokemonInformation) *battleSide {
+		side := &battleSide{name: name, members: []*battler{{pokemon: pokemon}}}
+		side.sendOut(1)
+		return side
+	}
+	pikachu := testPokemon(20, 40, 30, "electric")
+	pikachu.Name, pikachu.Hp, pikachu.MaxHp = "pikachu", 50, 50
+	geodude := testPokemon(20, 40, 30, "rock", "ground")
+	geodude.Name, geodude.Hp, geodude.MaxHp = "geodude", 50, 50
+	b := &battle{player: newSide("you", pikachu), opponent: newSide("wild", geodude), slots: 1}
+	b.log = startBattleLog(b, 7)
+	if b.log == nil {
+		t.Fatal("expected the battle log to be created")
+	}
+	id := strings.TrimSuffix(filepath.Base(b.log.file.Name()), ".jsonl")
+
+	b.turn = 1
+	b.execute(battleAction{side: b.player, actor: b.player.current(), move: testMove("thunder-shock", "electric", "special", 40), switchTo: -1})
+	b.turn = 2
+	b.execute(battleAction{side: b.player, actor: b.player.current(), move: testMove("quick-attack", "normal", "physical", 40), switchTo: -1})
+	b.record(battleEvent{Kind: "outcome", Outcome: "won"})
+	b.log.close()
+
+	events, err := readBattleLog(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []string{}
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	expected := []string{"start", "move", "immune", "move", "damage", "outcome"}
+	if !slices.Equal(kinds, expected) {
+		t.Fatalf("expected events %v, got %v", expected, kinds)
+	}
+	if events[0].Seed != 7 || events[0].Player[0].Name != "pikachu" || events[0].Enemy[0].Name != "geodude" {
+		t.Errorf("expected the start to hold the seed and both teams, got %+v", events[0])
+	}
+	if events[2].Pokemon != "geodude" || events[2].Move != "thunder-shock" || events[2].Turn != 1 {
+		t.Errorf("expected geodude to be immune to thunder-shock on turn 1, got %+v", events[2])
+	}
+	if events[4].Turn != 2 || events[4].Damage <= 0 || events[4].Hp != 50-events[4].Damage {
+		t.Errorf("expected quick-attack damage on turn 2, got %+v", events[4])
+	}
+}
+
+func TestReadBattleLogRejectsPaths(t *testing.T) {
+	cases := []string{"../../secret", "..", "battles/20240101_120000", `..\save`, ""}
+	for _, id := range cases {
+		if _, err := readBattleLog(id); err == nil || !strings.Contains(err.Error(), "invalid battle id") {
+			t.Errorf("%q: expected an invalid id error, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// boxSize is how many pokemon fit in one PC box.
+const boxSize = 30
+
+// Box is a named PC box, Members holds Pokedex keys.
+type Box struct {
+	Name    string
+	Members []string
+}
+
+// Boxes store every caught pokemon that is not in the party.
+var Boxes []Box
+
+func findBox(name string) (int, bool) {
+	for i, box := range Boxes {
+		if box.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// boxOf returns the index of the box holding the pokemon, or -1.
+func boxOf(key string) int {
+	for i, box := range Boxes {
+		for _, member := range box.Members {
+			if member == key {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func removeFromBoxes(key string) {
+	for i, box := range Boxes {
+		for j, member := range box.Members {
+			if member == key {
+				Boxes[i].Members = append(box.Members[:j], box.Members[j+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// boxWithRoom returns the first box with space, a new box is made when they are all full.
+func boxWithRoom() int {
+	for i, box := range Boxes {
+		if len(box.Members) < boxSize {
+			return i
+		}
+	}
+	Boxes = append(Boxes, Box{Name: unusedBoxName()})
+	return len(Boxes) - 1
+}
+
+// unusedBoxName returns the first box-N name no box has yet, players can name their own boxes like that too.
+func unusedBoxName() string {
+	for n := len(Boxes) + 1; ; n++ {
+		name := fmt.Sprintf("box-%d", n)
+		if _, ok := findBox(name); !ok {
+			return name
+		}
+	}
+}
+
+// storeInBox puts the pokemon in the named box, or the first one with room when the name is empty.
+// A box that does not exist yet is made.
+func storeInBox(key, boxName string) (string, bool) {
+	index, ok := findBox(boxName)
+	switch {
+	case boxName == "":
+		index = boxWithRoom()
+	case !ok:
+		Boxes = append(Boxes, Box{Name: boxName})
+		index = len(Boxes) - 1
+	case len(Boxes[index].Members) >= boxSize:
+		fmt.Printf("%s is full, a box holds at most %d pokemon\n", boxName, boxSize)
+		return "", false
+	}
+	Boxes[index].Members = append(Boxes[index].Members, key)
+	return Boxes[index].Name, true
+}
+
+// ensureBoxed puts caught pokemon that are in neither the party nor a box into a box
+// and forgets box entries of pokemon that are gone.
+func ensureBoxed() {
+	for i, box := range Boxes {
+		kept := []string{}
+		for _, member := range box.Members {
+			if _, ok := PokeDex[member]; ok && !inParty(member) {
+				kept = append(kept, member)
+			}
+		}
+		Boxes[i].Members = kept
+	}
+	for _, key := range sortedKeys() {
+		if !inParty(key) && boxOf(key) < 0 {
+			storeInBox(key, "")
+		}
+	}
+}
+
+func printBox(box Box) {
+	fmt.Printf("%s (%d/%d)\n", orange(box.Name), len(box.Members), boxSize)
+	for _, member := range box.Members {
+		pokemon := PokeDex[member]
+		fmt.Printf("- %s level %d\n", label(pokemon), pokemon.Level)
+	}
+}
+
+func boxList(boxName string) error {
+	if boxName != "" {
+		index, ok := findBox(boxName)
+		if !ok {
+			fmt.Printf("There is no box called %s\n", boxName)
+			return nil
+		}
+		printBox(Boxes[index])
+		return nil
+	}
+	if len(Boxes) == 0 {
+		fmt.Println("Your PC boxes are empty")
+		return nil
+	}
+	for _, box := range Boxes {
+		printBox(box)
+	}
+	return nil
+}
+
+// boxDeposit moves a party pokemon into a box, the party has to keep at least one pokemon.
+func boxDeposit(ref, boxName string) error {
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	name := displayName(PokeDex[key])
+	if !inParty(key) {
+		fmt.Printf("%s is not in your party\n", yellow(name))
+		return nil
+	}
+	if len(Party) == 1 {
+		fmt.Println("You can't deposit your last party pokemon")
+		return nil
+	}
+	stored, ok := storeInBox(key, boxName)
+	if !ok {
+		return nil
+	}
+	for i, member := range Party {
+		if member == key {
+			Party = append(Party[:i], Party[i+1:]...)
+			break
+		}
+	}
+	fmt.Printf("%s was sent to %s\n", yellow(name), orange(stored))
+	return nil
+}
+
+// boxWithdraw moves a boxed pokemon into the party.
+func boxWithdraw(ref string) error {
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	name := displayName(PokeDex[key])
+	if inParty(key) {
+		fmt.Printf("%s is already in your party\n", yellow(name))
+		return nil
+	}
+	if len(Party) >= maxPartySize {
+		fmt.Printf("Your party is full, a party can hold at most %d pokemon\n", maxPartySize)
+		return nil
+	}
+	removeFromBoxes(key)
+	Party = append(Party, key)
+	fmt.Printf("%s joined your party\n", yellow(name))
+	return nil
+}
+
+func boxMove(ref, boxName string) error {
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	name := displayName(PokeDex[key])
+	from := boxOf(key)
+	if from < 0 {
+		fmt.Printf("%s is in your party, use box deposit to put it in a box\n", yellow(name))
+		return nil
+	}
+	if Boxes[from].Name == boxName {
+		fmt.Printf("%s is already in %s\n", yellow(name), boxName)
+		return nil
+	}
+	removeFromBoxes(key)
+	if _, ok := storeInBox(key, boxName); !ok {
+		Boxes[from].Members = append(Boxes[from].Members, key)
+		return nil
+	}
+	fmt.Printf("%s was moved to %s\n", yellow(name), orange(boxName))
+	return nil
+}
+
+func commandBox(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return boxList("")
+	}
+	switch fields[0] {
+	case "list":
+		if len(fields) > 2 {
+			return fmt.Errorf("usage: box list [box]")
+		}
+		return boxList(strings.Join(fields[1:], ""))
+	case "move":
+		if len(fields) != 3 {
+			return fmt.Errorf("usage: box move <pokemon> <box>")
+		}
+		return boxMove(fields[1], fields[2])
+	case "deposit":
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("usage: box deposit <pokemon> [box]")
+		}
+		return boxDeposit(fields[1], strings.Join(fields[2:], ""))
+	case "withdraw":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: box withdraw <pokemon>")
+		}
+		return boxWithdraw(fields[1])
+	}
+	return fmt.Errorf("unknown box command %s, use list, move, deposit or withdraw", fields[0])
+}
+
+// commandRelease lets a caught pokemon go after the player confirms, its held item goes back in the bag.
+func commandRelease(_ *Config, ref string) error {
+	if ref == "" {
+		return fmt.Errorf("usage: release <pokemon>")
+	}
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	pokemon := PokeDex[key]
+	if inParty(key) && len(Party) == 1 {
+		fmt.Println("You can't release your last party pokemon")
+		return nil
+	}
+	fmt.Printf("Are you sure you want to release %s? (y/n):", label(pokemon))
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() || strings.TrimSpace(strings.ToLower(scanner.Text())) != "y" {
+		fmt.Printf("%s stays with you\n", yellow(displayName(pokemon)))
+		return nil
+	}
+	releasePokemon(key)
+	fmt.Printf("%s was released. Bye-bye, %s!\n", yellow(displayName(pokemon)), displayName(pokemon))
+	return nil
+}
+
+func releasePokemon(key string) {
+	if item := PokeDex[key].HeldItem; item != "" {
+		addItem(item, 1)
+	}
+	delete(PokeDex, key)
+	removeFromBoxes(key)
+	for i, member := range Party {
+		if member == key {
+			Party = append(Party[:i], Party[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+)
+
+func boxTestPokedex(count int) {
+	PokeDex = make(map[string]PokemonInformation)
+	NextID = 1
+	for range count {
+		id := newID()
+		PokeDex[id] = PokemonInformation{ID: id, Name: "pidgey" + id}
+	}
+}
+
+func TestEnsureBoxed(t *testing.T) {
+	boxTestPokedex(boxSize + 3)
+	Party = []string{"1", "2"}
+	Boxes = []Box{{Name: "favourites", Members: []string{"3", "gone"}}}
+	ensureBoxed()
+
+	if len(Boxes) != 2 {
+		t.Fatalf("expected the favourites box and one new box, got %d boxes", len(Boxes))
+	}
+	if len(Boxes[0].Members) != boxSize || Boxes[0].Members[0] != "3" {
+		t.Errorf("expected favourites to keep #3 first and fill up, got %v", Boxes[0].Members)
+	}
+	if Boxes[1].Name != "box-2" || len(Boxes[1].Members) != 1 || Boxes[1].Members[0] != "33" {
+		t.Errorf("expected #33 in a new box-2, got %+v", Boxes[1])
+	}
+	if boxOf("1") >= 0 || boxOf("2") >= 0 {
+		t.Error("party pokemon should not be boxed")
+	}
+}
+
+func TestBoxWithRoomSkipsTakenNames(t *testing.T) {
+	full := make([]string, boxSize)
+	Boxes = []Box{{Name: "favourites", Members: full}, {Name: "box-3", Members: full}}
+	defer func() { Boxes = nil }()
+	index := boxWithRoom()
+	if Boxes[index].Name != "box-4" {
+		t.Errorf("expected the new box to skip the player's box-3, got %s", Boxes[index].Name)
+	}
+	if i, _ := findBox("box-3"); i != 1 {
+		t.Errorf("expected box-3 to still be the player's box, got box %d", i)
+	}
+}
+
+func TestDepositAndWithdraw(t *testing.T) {
+	boxTestPokedex(3)
+	Party = []string{"1", "2"}
+	Boxes = []Box{{Name: "box-1", Members: []string{"3"}}}
+
+	boxDeposit("pidgey2", "")
+	if len(Party) != 1 || boxOf("2") != 0 {
+		t.Errorf("expected #2 to move to box-1, party %v boxes %+v", Party, Boxes)
+	}
+	boxDeposit("pidgey1", "")
+	if len(Party) != 1 {
+		t.Error("expected the last party pokemon to stay")
+	}
+	boxMove("#3", "favourites")
+	if index, ok := findBox("favourites"); !ok || boxOf("3") != index {
+		t.Errorf("expected #3 in a new favourites box, got %+v", Boxes)
+	}
+	boxWithdraw("3")
+	if !inParty("3") || boxOf("3") >= 0 {
+		t.Errorf("expected #3 back in the party, party %v boxes %+v", Party, Boxes)
+	}
+}
+
+func TestReleasePokemon(t *testing.T) {
+	boxTestPokedex(2)
+	pokemon := PokeDex["2"]
+	pokemon.HeldItem = "oran-berry"
+	PokeDex["2"] = pokemon
+	Party = []string{"1"}
+	Boxes = []Box{{Name: "box-1", Members: []string{"2"}}}
+	Bag = map[string]int{}
+
+	releasePokemon("2")
+	if _, ok := PokeDex["2"]; ok || boxOf("2") >= 0 {
+		t.Error("expected #2 to be gone")
+	}
+	if Bag["oran-berry"] != 1 {
+		t.Error("expected the held berry to go back into the bag")
+	}
+}
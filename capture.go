@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// shakeDelay is the pause between the shakes of a thrown ball.
+var shakeDelay = 500 * time.Millisecond
+
+// ballThrow is what a ball's catch modifier depends on, turn is 1 for a throw outside of battle.
+type ballThrow struct {
+	target PokemonInformation
+	turn   int
+}
+
+// balls maps every ball to its catch modifier, specialty balls only help under their condition.
+var balls = map[string]func(throw ballThrow) float64{
+	"poke-ball":  func(ballThrow) float64 { return 1 },
+	"great-ball": func(ballThrow) float64 { return 1.5 },
+	"ultra-ball": func(ballThrow) float64 { return 2 },
+	// the master ball never fails
+	"master-ball": func(ballThrow) float64 { return 255 },
+	"net-ball": func(throw ballThrow) float64 {
+		if hasType(throw.target, "water") || hasType(throw.target, "bug") {
+			return 3.5
+		}
+		return 1
+	},
+	"quick-ball": func(throw ballThrow) float64 {
+		if throw.turn <= 1 {
+			return 5
+		}
+		return 1
+	},
+	"timer-ball": func(throw ballThrow) float64 {
+		return min(1+float64(throw.turn)*1229/4096, 4)
+	},
+	"nest-ball": func(throw ballThrow) float64 {
+		return max(float64(41-throw.target.Level)/10, 1)
+	},
+	"repeat-ball": func(throw ballThrow) float64 {
+		if DexCaught[speciesName(throw.target)] {
+			return 3.5
+		}
+		return 1
+	},
+}
+
+// ballName turns the short name players type, like great, into the item name great-ball.
+func ballName(name string) string {
+	if strings.HasSuffix(name, "-ball") {
+		return name
+	}
+	return name + "-ball"
+}
+
+// readyBall checks that the player carries the ball and prints why not.
+func readyBall(ball string) bool {
+	if _, ok := balls[ball]; !ok {
+		fmt.Printf("There is no ball called %s\n", ball)
+		return false
+	}
+	if Bag[ball] <= 0 {
+		fmt.Printf("You have no %s left, buy some with the %s command\n", ball, blue("shop"))
+		return false
+	}
+	return true
+}
+
+func captureRate(pokemon PokemonInformation) (int, error) {
+	species, err := getSpecies(pokemon)
+	if err != nil {
+		return 0, err
+	}
+	return species.CaptureRate, nil
+}
+
+// statusBonus is the capture bonus a status condition gives.
+func statusBonus(status string) float64 {
+	switch status {
+	case "sleep", "freeze":
+		return 2.5
+	case "paralysis", "poison", "burn":
+		return 1.5
+	}
+	return 1
+}
+
+// captureValue is the modified catch rate of the capture formula, it rises as the pokemon's hp drops.
+func captureValue(pokemon PokemonInformation, rate int, ball float64, status string) float64 {
+	maxHp := max(pokemon.MaxHp, 1)
+	hp := min(max(pokemon.Hp, 1), maxHp)
+	a := float64((3*maxHp-2*hp)*rate) * ball / float64(3*maxHp)
+	return a * statusBonus(status)
+}
+
+// shakeThreshold is the number each of the four shake checks has to roll under out of 65536.
+func shakeThreshold(a float64) int {
+	if a >= 255 {
+		return 65536
+	}
+	if a <= 0 {
+		return 0
+	}
+	return int(1048560 / math.Sqrt(math.Sqrt(16711680/a)))
+}
+
+// throwBall rolls the four shake checks, it returns how many times the ball shook and whether it caught the pokemon.
+func throwBall(a float64, intn func(int) int) (int, bool) {
+	threshold := shakeThreshold(a)
+	for check := 0; check < 4; check++ {
+		if intn(65536) >= threshold {
+			return check, false
+		}
+	}
+	return 3, true
+}
+
+func printThrow(pokemonName string, shakes int, caught bool) {
+	for range shakes {
+		time.Sleep(shakeDelay)
+		fmt.Println(orange("...shake..."))
+	}
+	time.Sleep(shakeDelay)
+	if caught {
+		fmt.Printf("Gotcha! %s was caught!\n", yellow(pokemonName))
+		return
+	}
+	messages := []string{
+		"Oh no! The pokemon broke free!",
+		"Aww! It appeared to be caught!",
+		"Aargh! Almost had it!",
+		"Gah! It was so close, too!",
+	}
+	fmt.Println(messages[shakes])
+}
+
+// keepCaught stores a caught pokemon under a new ID and lets it join the party when there is room.
+func keepCaught(pokemon PokemonInformation) error {
+	rate, err := getGrowthRate(pokemon)
+	if err != nil {
+		return err
+	}
+	nature, err := randomNature()
+	if err != nil {
+		return err
+	}
+	pokemon.Level = max(pokemon.Level, 1)
+	pokemon.Experience = experienceForLevel(rate, pokemon.Level)
+	pokemon.GrowthRate = rate.Name
+	pokemon.IVs = rollIndividualValues(pokemon)
+	pokemon.EVs = make(map[string]int)
+	pokemon.Nature = nature
+	if pokemon.Ability == "" {
+		pokemon.Ability = chooseAbility(pokemon)
+	}
+	if pokemon.Moves == nil {
+		pokemon.Moves = make(map[string]Move)
+	}
+	resetStats(&pokemon)
+	pokemon.ID = newID()
+	PokeDex[pokemon.ID] = pokemon
+	markCaught(speciesName(pokemon))
+	markVariants(pokemon)
+	fmt.Printf("%s was registered as %s\n", yellow(pokemon.Name), boldYellow("#"+pokemon.ID))
+	if len(Party) < maxPartySize {
+		Party = append(Party, pokemon.ID)
+		fmt.Printf("%s joined your party\n", yellow(pokemon.Name))
+		return nil
+	}
+	box, _ := storeInBox(pokemon.ID, "")
+	fmt.Printf("Your party is full, %s was sent to %s\n", yellow(pokemon.Name), orange(box))
+	return nil
+}
+
+// catchAction parses "catch [ball]" in a wild battle, only the last wild pokemon on the field can be caught.
+func catchAction(b *battle, side *battleSide, active *battler, ball string) (battleAction, bool) {
+	if b.trainer != nil || b.friendly {
+		fmt.Println("You can't catch another trainer's pokemon!")
+		return battleAction{}, false
+	}
+	targets := b.other(side).fighters()
+	if len(targets) != 1 {
+		fmt.Println("You can't aim a ball while two wild pokemon are on the field!")
+		return battleAction{}, false
+	}
+	if ball == "" {
+		ball = "poke-ball"
+	}
+	ball = ballName(ball)
+	if !readyBall(ball) {
+		return battleAction{}, false
+	}
+	return battleAction{side: side, actor: active, ball: ball, target: targets[0], switchTo: -1}, true
+}
+
+// throwAt throws the action's ball at the wild pokemon, a catch ends the battle.
+func (b *battle) throwAt(action battleAction) {
+	target := action.target
+	rate, err := captureRate(target.pokemon)
+	if err != nil {
+		fmt.Printf("Unable to throw the %s: %v\n", action.ball, err)
+		return
+	}
+	if !takeItem(action.ball) {
+		fmt.Printf("You have no %s left\n", action.ball)
+		return
+	}
+	fmt.Printf("You threw a %s at %s!\n", cyan(action.ball), yellow(target.pokemon.Name))
+	modifier := balls[action.ball](ballThrow{target: target.pokemon, turn: b.turn})
+	shakes, caught := throwBall(captureValue(target.pokemon, rate, modifier, target.status), battleRand.Intn)
+	printThrow(target.pokemon.Name, shakes, caught)
+	b.record(battleEvent{Kind: "throw", Side: b.sideName(action.side), Pokemon: target.pokemon.Name, Item: action.ball, Shakes: shakes})
+	if caught {
+		b.caught = target
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCaptureValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		hp       int
+		status   string
+		ball     float64
+		expected float64
+	}{
+		{name: "full hp", hp: 100, ball: 1, expected: 15},
+		{name: "one hp", hp: 1, ball: 1, expected: 44.7},
+		{name: "asleep", hp: 100, status: "sleep", ball: 1, expected: 37.5},
+		{name: "paralysed", hp: 100, status: "paralysis", ball: 1, expected: 22.5},
+		{name: "better ball", hp: 100, ball: 2, expected: 30},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pokemon := PokemonInformation{Hp: c.hp, MaxHp: 100}
+			actual := captureValue(pokemon, 45, c.ball, c.status)
+			if math.Abs(actual-c.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestThrowBall(t *testing.T) {
+	rolls := func(values ...int) func(int) int {
+		return func(int) int {
+			value := values[0]
+			values = values[1:]
+			return value
+		}
+	}
+	threshold := shakeThreshold(15)
+	if threshold != 32274 {
+		t.Errorf("expected a shake threshold of 32274, got %d", threshold)
+	}
+	if shakes, caught := throwBall(15, rolls(threshold)); shakes != 0 || caught {
+		t.Errorf("expected the ball to break open at once, got %d shakes and caught %v", shakes, caught)
+	}
+	if shakes, caught := throwBall(15, rolls(0, 0, threshold)); shakes != 2 || caught {
+		t.Errorf("expected two shakes, got %d shakes and caught %v", shakes, caught)
+	}
+	if shakes, caught := throwBall(15, rolls(0, 0, 0, threshold-1)); shakes != 3 || !caught {
+		t.Errorf("expected a catch after three shakes, got %d shakes and caught %v", shakes, caught)
+	}
+	if _, caught := throwBall(255, rolls(65535, 65535, 65535, 65535)); !caught {
+		t.Error("expected a capture value of 255 to always catch")
+	}
+}
+
+func TestBallModifiers(t *testing.T) {
+	DexCaught = map[string]bool{"magikarp": true, "raticate": true}
+	defer func() { DexCaught = nil }()
+	water := testPokemon(5, 10, 10, "water")
+	water.Name = "magikarp"
+	fire := testPokemon(30, 10, 10, "fire")
+	fire.Name = "charmander"
+	alolan := testPokemon(30, 10, 10, "dark", "normal")
+	alolan.Name, alolan.Species = "raticate-alola", NamedAPIResource{Name: "raticate"}
+	cases := []struct {
+		ball     string
+		throw    ballThrow
+		expected float64
+	}{
+		{ball: "great-ball", throw: ballThrow{target: fire, turn: 1}, expected: 1.5},
+		{ball: "net-ball", throw: ballThrow{target: water, turn: 1}, expected: 3.5},
+		{ball: "net-ball", throw: ballThrow{target: fire, turn: 1}, expected: 1},
+		{ball: "quick-ball", throw: ballThrow{target: fire, turn: 1}, expected: 5},
+		{ball: "quick-ball", throw: ballThrow{target: fire, turn: 2}, expected: 1},
+		{ball: "timer-ball", throw: ballThrow{target: fire, turn: 20}, expected: 4},
+		{ball: "nest-ball", throw: ballThrow{target: water, turn: 1}, expected: 3.6},
+		{ball: "nest-ball", throw: ballThrow{target: fire, turn: 1}, expected: 1.1},
+		{ball: "repeat-ball", throw: ballThrow{target: water, turn: 1}, expected: 3.5},
+		{ball: "repeat-ball", throw: ballThrow{target: fire, turn: 1}, expected: 1},
+		{ball: "repeat-ball", throw: ballThrow{target: alolan, turn: 1}, expected: 3.5},
+	}
+	for _, c := range cases {
+		t.Run(c.ball, func(t *testing.T) {
+			actual := balls[c.ball](c.throw)
+			if math.Abs(actual-c.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+	if ballName("great") != "great-ball" || ballName("net-ball") != "net-ball" {
+		t.Error("expected short ball names to get the -ball suffix")
+	}
+}
+
+func TestCatchOncePerFind(t *testing.T) {
+	useTestCache(t)
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/pidgey", []byte(`{"name": "pidgey", "capture_rate": 255, "growth_rate": {"name": "medium-slow", "url": "https://pokeapi.co/api/v2/growth-rate/4/"}}`))
+	cache.Add("https://pokeapi.co/api/v2/growth-rate/4/", []byte(`{"name": "medium-slow", "levels": [{"level": 1, "experience": 0}, {"level": 2, "experience": 9}, {"level": 3, "experience": 57}]}`))
+	cache.Add("https://pokeapi.co/api/v2/nature?limit=25", []byte(`{"results": [{"name": "hardy", "url": "https://pokeapi.co/api/v2/nature/1/"}]}`))
+	cache.Add("https://pokeapi.co/api/v2/nature/1/", []byte(`{"name": "hardy"}`))
+	PokeDex, Party, Boxes, NextID = map[string]PokemonInformation{}, nil, nil, 1
+	Bag = map[string]int{"master-ball": 2}
+	catchablePokemon = map[string]PokemonInformation{"pidgey": {Name: "pidgey", Level: 3}}
+	delay := shakeDelay
+	shakeDelay = 0
+	defer func() { PokeDex, Party, Bag, catchablePokemon, shakeDelay = nil, nil, nil, nil, delay }()
+
+	for i := 0; i < 2; i++ {
+		err := commandCatch(nil, "pidgey --ball master-ball")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(PokeDex) != 1 {
+		t.Errorf("expected one pidgey to be caught, got %d pokemon", len(PokeDex))
+	}
+	if Bag["master-ball"] != 1 {
+		t.Errorf("expected the second catch to fail before throwing, %d master balls left", Bag["master-ball"])
+	}
+	if _, ok := catchablePokemon["pidgey"]; ok {
+		t.Error("expected the caught pidgey to be gone")
+	}
+}
+
+func TestThrowKeepsBallWithoutSpecies(t *testing.T) {
+	useTestCache(t)
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/broken/", []byte(`not json`))
+	Bag = map[string]int{"poke-ball": 1}
+	defer func() { Bag = nil }()
+	target := &battler{pokemon: PokemonInformation{Name: "broken", Species: NamedAPIResource{Name: "broken", URL: "https://pokeapi.co/api/v2/pokemon-species/broken/"}}}
+	b := &battle{player: &battleSide{}, opponent: &battleSide{members: []*battler{target}}}
+	b.throwAt(battleAction{side: b.player, ball: "poke-ball", target: target})
+	if Bag["poke-ball"] != 1 || b.caught != nil {
+		t.Errorf("expected the ball to stay in the bag when the species is unknown, %d left", Bag["poke-ball"])
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+)
+
+// typeChart holds every attacking type matchup that is not neutral.
+var typeChart = map[string]map[string]float64{
+	"normal":   {"rock": 0.5, "ghost": 0, "steel": 0.5},
+	"fire":     {"fire": 0.5, "water": 0.5, "grass": 2, "ice": 2, "bug": 2, "rock": 0.5, "dragon": 0.5, "steel": 2},
+	"water":    {"fire": 2, "water": 0.5, "grass": 0.5, "ground": 2, "rock": 2, "dragon": 0.5},
+	"electric": {"water": 2, "electric": 0.5, "grass": 0.5, "ground": 0, "flying": 2, "dragon": 0.5},
+	"grass":    {"fire": 0.5, "water": 2, "grass": 0.5, "poison": 0.5, "ground": 2, "flying": 0.5, "bug": 0.5, "rock": 2, "dragon": 0.5, "steel": 0.5},
+	"ice":      {"fire": 0.5, "water": 0.5, "grass": 2, "ice": 0.5, "ground": 2, "flying": 2, "dragon": 2, "steel": 0.5},
+	"fighting": {"normal": 2, "ice": 2, "poison": 0.5, "flying": 0.5, "psychic": 0.5, "bug": 0.5, "rock": 2, "ghost": 0, "dark": 2, "steel": 2, "fairy": 0.5},
+	"poison":   {"grass": 2, "poison": 0.5, "ground": 0.5, "rock": 0.5, "ghost": 0.5, "steel": 0, "fairy": 2},
+	"ground":   {"fire": 2, "electric": 2, "grass": 0.5, "poison": 2, "flying": 0, "bug": 0.5, "rock": 2, "steel": 2},
+	"flying":   {"electric": 0.5, "grass": 2, "fighting": 2, "bug": 2, "rock": 0.5, "steel": 0.5},
+	"psychic":  {"fighting": 2, "poison": 2, "psychic": 0.5, "dark": 0, "steel": 0.5},
+	"bug":      {"fire": 0.5, "grass": 2, "fighting": 0.5, "poison": 0.5, "flying": 0.5, "psychic": 2, "ghost": 0.5, "dark": 2, "steel": 0.5, "fairy": 0.5},
+	"rock":     {"fire": 2, "ice": 2, "fighting": 0.5, "ground": 0.5, "flying": 2, "bug": 2, "steel": 0.5},
+	"ghost":    {"normal": 0, "psychic": 2, "ghost": 2, "dark": 0.5},
+	"dragon":   {"dragon": 2, "steel": 0.5, "fairy": 0},
+	"dark":     {"fighting": 0.5, "psychic": 2, "ghost": 2, "dark": 0.5, "fairy": 0.5},
+	"steel":    {"fire": 0.5, "water": 0.5, "electric": 0.5, "ice": 2, "rock": 2, "steel": 0.5, "fairy": 2},
+	"fairy":    {"fire": 0.5, "fighting": 2, "poison": 0.5, "dragon": 2, "dark": 2, "steel": 0.5},
+}
+
+// damageRoll holds the random parts of a single hit and the battle conditions around it,
+// so the formula itself stays deterministic.
+type damageRoll struct {
+	crit   bool
+	random int // 85 to 100 inclusive
+	// spread is set when the move hits more than one pokemon
+	spread  bool
+	weather string
+	terrain string
+}
+
+func hasType(pokemon PokemonInformation, typeName string) bool {
+	for _, t := range pokemon.Types {
+		if t.Type.Name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func typeEffectiveness(moveType string, defender PokemonInformation) float64 {
+	if abilityImmune(defender, moveType) {
+		return 0
+	}
+	multiplier := 1.0
+	for _, t := range defender.Types {
+		if m, ok := typeChart[moveType][t.Type.Name]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// fixedDamageMoves have a null power in PokeAPI and always deal the same damage, level means the user's level.
+var fixedDamageMoves = map[string]int{
+	"sonic-boom":   20,
+	"dragon-rage":  40,
+	"seismic-toss": -1,
+	"night-shade":  -1,
+}
+
+// fixedDamage returns the damage of a move from fixedDamageMoves.
+func fixedDamage(attacker PokemonInformation, move Move) (int, bool) {
+	damage, ok := fixedDamageMoves[move.Name]
+	if damage < 0 {
+		damage = attacker.Level
+	}
+	return damage, ok
+}
+
+// supportedMove reports whether battles can use the move. Other moves with a null power, like low-kick,
+// depend on things that are not tracked, and of the status moves only weather and terrain have an effect.
+func supportedMove(move Move) bool {
+	if move.DamageClass.Name == "status" {
+		return fieldMove(move)
+	}
+	_, fixed := fixedDamageMoves[move.Name]
+	return move.Power > 0 || fixed
+}
+
+// calculateDamage applies the generation V+ damage formula, rounding down after every step like the games do.
+// Fixed damage moves ignore the formula but not type immunities, other moves with a null power deal no damage.
+func calculateDamage(attacker, defender PokemonInformation, move Move, roll damageRoll) int {
+	if damage, ok := fixedDamage(attacker, move); ok {
+		if typeEffectiveness(move.Type.Name, defender) == 0 {
+			return 0
+		}
+		return damage
+	}
+	if move.Power == 0 {
+		return 0
+	}
+	attack, defense := attacker.Attack, defender.Defense
+	if move.DamageClass.Name == "special" {
+		attack, defense = attacker.SpecialAttack, defender.SpecialDefense
+	}
+	attack = attack * abilityAttackPercent(attacker, move) / 100
+	if roll.weather == "sandstorm" && move.DamageClass.Name == "special" && hasType(defender, "rock") {
+		defense = defense * 3 / 2
+	}
+	if defense < 1 {
+		defense = 1
+	}
+	power := terrainPower(attacker, defender, move, roll.terrain)
+	damage := ((2*attacker.Level/5+2)*power*attack/defense)/50 + 2
+	if roll.spread {
+		damage = damage * spreadMultiplier / 100
+	}
+	damage = damage * weatherPercent(move.Type.Name, roll.weather) / 100
+	if roll.crit {
+		damage = damage * 3 / 2
+	}
+	damage = damage * roll.random / 100
+	if hasType(attacker, move.Type.Name) {
+		damage = damage * 3 / 2
+	}
+	effectiveness := typeEffectiveness(move.Type.Name, defender)
+	if effectiveness == 0 {
+		return 0
+	}
+	damage = int(float64(damage) * effectiveness)
+	if damage < 1 {
+		damage = 1
+	}
+	return damage
+}
+
+// moveHits rolls the accuracy check, moves with a null accuracy never miss.
+func moveHits(move Move) bool {
+	if move.Accuracy == 0 {
+		return true
+	}
+	return battleRand.Intn(100) < move.Accuracy
+}
+
+// isCriticalHit uses the generation VII+ critical hit chances per stage.
+func isCriticalHit(stage int) bool {
+	switch {
+	case stage <= 0:
+		return battleRand.Intn(24) == 0
+	case stage == 1:
+		return battleRand.Intn(8) == 0
+	case stage == 2:
+		return battleRand.Intn(2) == 0
+	default:
+		return true
+	}
+}
+
+// calculateDamageMove rolls accuracy, crits and the random factor for a move under the given conditions
+// and reports the damage dealt with whether the move was a "hit", a "miss" or the target was "immune".
+func calculateDamageMove(attackerPokemon, pokemon PokemonInformation, move Move, roll damageRoll) (int, string) {
+	if !moveHits(move) {
+		fmt.Printf("%s %s %s!\n", pokemon.Name, yellow("dodged"), move.Name)
+		return 0, "miss"
+	}
+	_, fixed := fixedDamage(attackerPokemon, move)
+	if move.Power == 0 && !fixed {
+		if move.DamageClass.Name != "status" {
+			fmt.Printf("%s had no effect\n", move.Name)
+		}
+		return 0, "hit"
+	}
+	if !fixed {
+		roll.crit = isCriticalHit(move.Meta.CritRate)
+		roll.random = 85 + battleRand.Intn(16)
+	}
+	damage := calculateDamage(attackerPokemon, pokemon, move, roll)
+	effectiveness := typeEffectiveness(move.Type.Name, pokemon)
+	switch {
+	case effectiveness == 0:
+		fmt.Printf("It doesn't affect %s...\n", pokemon.Name)
+		return 0, "immune"
+	case effectiveness > 1:
+		fmt.Println(boldGreen("It's super effective!"))
+	case effectiveness < 1:
+		fmt.Println(yellow("It's not very effective..."))
+	}
+	if roll.crit {
+		fmt.Println(boldRed("A critical hit!"))
+	}
+	fmt.Printf("%s dealt: %s\n", boldRed("Damage"), red(fmt.Sprintf("%d", damage)))
+	return damage, "hit"
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"testing"
+)
+
+func testPokemon(level, attack, defense int, types ...string) PokemonInformation {
+	pokemon := PokemonInformation{
+		Level:          level,
+		Attack:         attack,
+		Defense:        defense,
+		SpecialAttack:  attack,
+		SpecialDefense: defense,
+	}
+	for _, t := range types {
+		pokemon.Types = append(pokemon.Types, PType{Type: TypeInfo{Name: t}})
+	}
+	return pokemon
+}
+
+func testMove(name, moveType, damageClass string, power int) Move {
+	var move Move
+	move.Name = name
+	move.Power = power
+	move.Type.Name = moveType
+	move.DamageClass.Name = damageClass
+	return move
+}
+
+func TestCalculateDamage(t *testing.T) {
+	cases := []struct {
+		name     string
+		attacker PokemonInformation
+		defender PokemonInformation
+		move     Move
+		roll     damageRoll
+		expected int
+	}{
+		{
+			name:     "neutral max roll",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("tackle", "normal", "physical", 80),
+			roll:     damageRoll{random: 100},
+			expected: 37,
+		},
+		{
+			name:     "neutral min roll",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("tackle", "normal", "physical", 80),
+			roll:     damageRoll{random: 85},
+			expected: 31,
+		},
+		{
+			name:     "spread move",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("tackle", "normal", "physical", 80),
+			roll:     damageRoll{random: 100, spread: true},
+			expected: 27,
+		},
+		{
+			name:     "rain boosts water",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("waterfall", "water", "physical", 80),
+			roll:     damageRoll{random: 100, weather: "rain"},
+			expected: 55,
+		},
+		{
+			name:     "sun weakens water",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("waterfall", "water", "physical", 80),
+			roll:     damageRoll{random: 100, weather: "sun"},
+			expected: 18,
+		},
+		{
+			name:     "electric terrain boosts grounded attacker",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("spark", "electric", "physical", 80),
+			roll:     damageRoll{random: 100, terrain: "electric"},
+			expected: 47,
+		},
+		{
+			name:     "electric terrain ignores flying attacker",
+			attacker: testPokemon(50, 100, 100, "flying"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("spark", "electric", "physical", 80),
+			roll:     damageRoll{random: 100, terrain: "electric"},
+			expected: 37,
+		},
+		{
+			name:     "critical hit",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("tackle", "normal", "physical", 80),
+			roll:     damageRoll{crit: true, random: 100},
+			expected: 55,
+		},
+		{
+			name:     "same type attack bonus",
+			attacker: testPokemon(50, 100, 100, "normal"),
+			defender: testPokemon(50, 100, 100, "fighting"),
+			move:     testMove("tackle", "normal", "physical", 80),
+			roll:     damageRoll{random: 100},
+			expected: 55,
+		},
+		{
+			name:     "super effective with stab",
+			attacker: testPokemon(50, 100, 100, "water"),
+			defender: testPokemon(50, 100, 100, "fire"),
+			move:     testMove("water-pulse", "water", "special", 80),
+			roll:     damageRoll{random: 100},
+			expected: 110,
+		},
+		{
+			name:     "double resisted",
+			attacker: testPokemon(50, 100, 100, "normal"),
+			defender: testPokemon(50, 100, 100, "water", "dragon"),
+			move:     testMove("ember", "fire", "special", 80),
+			roll:     damageRoll{random: 100},
+			expected: 9,
+		},
+		{
+			name:     "immune",
+			attacker: testPokemon(50, 100, 100, "normal"),
+			defender: testPokemon(50, 100, 100, "ghost"),
+			move:     testMove("tackle", "normal", "physical", 80),
+			roll:     damageRoll{random: 100},
+			expected: 0,
+		},
+		{
+			name:     "level damage",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "normal"),
+			move:     testMove("seismic-toss", "fighting", "physical", 0),
+			roll:     damageRoll{random: 100},
+			expected: 50,
+		},
+		{
+			name:     "fixed damage ignores resistances",
+			attacker: testPokemon(30, 100, 100, "dragon"),
+			defender: testPokemon(30, 100, 100, "steel"),
+			move:     testMove("dragon-rage", "dragon", "special", 0),
+			roll:     damageRoll{random: 100},
+			expected: 40,
+		},
+		{
+			name:     "fixed damage keeps immunities",
+			attacker: testPokemon(50, 100, 100, "ghost"),
+			defender: testPokemon(50, 100, 100, "normal"),
+			move:     testMove("night-shade", "ghost", "special", 0),
+			roll:     damageRoll{random: 100},
+			expected: 0,
+		},
+		{
+			name:     "unsupported null power",
+			attacker: testPokemon(50, 100, 100, "fighting"),
+			defender: testPokemon(50, 100, 100, "normal"),
+			move:     testMove("low-kick", "fighting", "physical", 0),
+			roll:     damageRoll{random: 100},
+			expected: 0,
+		},
+		{
+			name:     "always at least one damage",
+			attacker: testPokemon(1, 5, 5, "normal"),
+			defender: testPokemon(1, 200, 200, "steel"),
+			move:     testMove("rock-throw", "rock", "physical", 10),
+			roll:     damageRoll{random: 85},
+			expected: 1,
+		},
+		{
+			name:     "glaceon ice fang on garchomp min roll",
+			attacker: testPokemon(75, 123, 100, "ice"),
+			defender: testPokemon(65, 100, 163, "dragon", "ground"),
+			move:     testMove("ice-fang", "ice", "physical", 65),
+			roll:     damageRoll{random: 85},
+			expected: 168,
+		},
+		{
+			name:     "glaceon ice fang on garchomp max roll",
+			attacker: testPokemon(75, 123, 100, "ice"),
+			defender: testPokemon(65, 100, 163, "dragon", "ground"),
+			move:     testMove("ice-fang", "ice", "physical", 65),
+			roll:     damageRoll{random: 100},
+			expected: 196,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := calculateDamage(c.attacker, c.defender, c.move, c.roll)
+			if actual != c.expected {
+				t.Errorf("expected damage %d, got %d", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMoveHitsNullAccuracy(t *testing.T) {
+	move := testMove("swift", "normal", "special", 60)
+	for i := 0; i < 100; i++ {
+		if !moveHits(move) {
+			t.Fatal("expected a move with null accuracy to never miss")
+		}
+	}
+}
+
+func TestSupportedMove(t *testing.T) {
+	cases := []struct {
+		move     Move
+		expected bool
+	}{
+		{move: testMove("tackle", "normal", "physical", 40), expected: true},
+		{move: testMove("seismic-toss", "fighting", "physical", 0), expected: true},
+		{move: testMove("sonic-boom", "normal", "special", 0), expected: true},
+		{move: testMove("low-kick", "fighting", "physical", 0), expected: false},
+		{move: testMove("rain-dance", "water", "status", 0), expected: true},
+		{move: testMove("growl", "normal", "status", 0), expected: false},
+	}
+	for _, c := range cases {
+		actual := supportedMove(c.move)
+		if actual != c.expected {
+			t.Errorf("%s: expected supported %v, got %v", c.move.Name, c.expected, actual)
+		}
+	}
+}
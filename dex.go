@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DexSeen and DexCaught track species by name, a released pokemon still counts as caught.
+var DexSeen map[string]bool
+var DexCaught map[string]bool
+
+// DexLanguage is the PokeAPI language code used for genus and flavor text.
+var DexLanguage = "en"
+
+// NamedAPIResourceList is a PokeAPI listing like /generation or /region.
+type NamedAPIResourceList struct {
+	Results []NamedAPIResource `json:"results"`
+}
+
+type Generation struct {
+	Name           string             `json:"name"`
+	MainRegion     NamedAPIResource   `json:"main_region"`
+	PokemonSpecies []NamedAPIResource `json:"pokemon_species"`
+}
+
+type Region struct {
+	Name      string             `json:"name"`
+	Locations []NamedAPIResource `json:"locations"`
+	Pokedexes []NamedAPIResource `json:"pokedexes"`
+}
+
+// RegionalDex is a PokeAPI /pokedex, like the national dex or the one of a region.
+type RegionalDex struct {
+	Name           string `json:"name"`
+	PokemonEntries []struct {
+		EntryNumber    int              `json:"entry_number"`
+		PokemonSpecies NamedAPIResource `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+}
+
+// speciesName is the species of a pokemon, forms like deoxys-normal belong to deoxys.
+func speciesName(pokemon PokemonInformation) string {
+	if pokemon.Species.Name != "" {
+		return pokemon.Species.Name
+	}
+	return pokemon.Name
+}
+
+// encounterSpecies resolves the species of a pokemon listed in an area by its /pokemon url.
+func encounterSpecies(url string) (string, error) {
+	var pokemon PokemonInformation
+	err := getJSON(url, &pokemon)
+	if err != nil {
+		return "", err
+	}
+	return speciesName(pokemon), nil
+}
+
+func markSeen(species string) {
+	if DexSeen == nil {
+		DexSeen = make(map[string]bool)
+	}
+	DexSeen[species] = true
+}
+
+func markCaught(species string) {
+	markSeen(species)
+	if DexCaught == nil {
+		DexCaught = make(map[string]bool)
+	}
+	DexCaught[species] = true
+}
+
+// registerCaughtSpecies marks everything in the Pokedex as caught, for saves from before the dex was tracked.
+func registerCaughtSpecies() {
+	for _, pokemon := range PokeDex {
+		markCaught(speciesName(pokemon))
+		markVariants(pokemon)
+	}
+}
+
+func getJSON(url string, v any) error {
+	data, err := GetData(cache, url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// completion counts how many of the species were seen and caught.
+func completion(species []string) (int, int) {
+	seen, caught := 0, 0
+	for _, name := range species {
+		if DexSeen[name] {
+			seen++
+		}
+		if DexCaught[name] {
+			caught++
+		}
+	}
+	return seen, caught
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+func printCompletion(name string, species []string) {
+	seen, caught := completion(species)
+	fmt.Printf("- %s: seen %d/%d, caught %d/%d (%s)\n", yellow(name), seen, len(species), caught, len(species), boldGreen(fmt.Sprintf("%.1f%%", percentage(caught, len(species)))))
+}
+
+func speciesOf(entries RegionalDex) []string {
+	species := []string{}
+	for _, entry := range entries.PokemonEntries {
+		species = append(species, entry.PokemonSpecies.Name)
+	}
+	return species
+}
+
+// printDexEntries lists the seen species in national dex order.
+func printDexEntries(national RegionalDex) {
+	entries := national.PokemonEntries
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].EntryNumber < entries[j].EntryNumber
+	})
+	fmt.Println(orange("Entries:"))
+	for _, entry := range entries {
+		name := entry.PokemonSpecies.Name
+		if !DexSeen[name] {
+			continue
+		}
+		status := "seen"
+		if DexCaught[name] {
+			status = boldGreen("caught")
+		}
+		fmt.Printf("#%04d %s %s\n", entry.EntryNumber, yellow(name), status)
+	}
+}
+
+// genus returns the genus in the language, falling back to english.
+func genus(species PokemonSpecies, language string) string {
+	fallback := ""
+	for _, g := range species.Genera {
+		if g.Language.Name == language {
+			return g.Genus
+		}
+		if g.Language.Name == "en" {
+			fallback = g.Genus
+		}
+	}
+	return fallback
+}
+
+// flavorText returns the newest flavor text in the language, the API wraps lines with newlines and form feeds.
+func flavorText(species PokemonSpecies, language string) string {
+	text := ""
+	for _, entry := range species.FlavorTextEntries {
+		if entry.Language.Name == language {
+			text = entry.FlavorText
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// genderRatio describes the gender_rate of a species, which counts females in eighths.
+func genderRatio(rate int) string {
+	if rate < 0 {
+		return "genderless"
+	}
+	female := percentage(rate, 8)
+	return fmt.Sprintf("%.1f%% male, %.1f%% female", 100-female, female)
+}
+
+func orUnknown(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// printDexEntry shows what the dex knows about a species, seen species only show what can be told from a distance.
+func printDexEntry(name string) error {
+	if !DexSeen[name] {
+		return fmt.Errorf("you have not seen %s yet", name)
+	}
+	species, err := getSpecies(PokemonInformation{Name: name})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", yellow(species.Name), cyan(genus(species, DexLanguage)))
+	fmt.Printf("%s %s\n%s %s\n%s %s\n", green("habitat:"), orUnknown(species.Habitat.Name), green("shape:"), orUnknown(species.Shape.Name), green("color:"), orUnknown(species.Color.Name))
+	if !DexCaught[name] {
+		fmt.Println(orange("Catch one to learn more about it"))
+		return nil
+	}
+	groups := []string{}
+	for _, group := range species.EggGroups {
+		groups = append(groups, group.Name)
+	}
+	fmt.Printf("%s %s\n%s %s\n", green("egg groups:"), strings.Join(groups, ", "), green("gender:"), genderRatio(species.GenderRate))
+	if species.IsLegendary {
+		fmt.Println(boldYellow("legendary"))
+	}
+	if species.IsMythical {
+		fmt.Println(boldYellow("mythical"))
+	}
+	if variants := variantsOf(name); len(variants) > 0 {
+		sort.Strings(variants)
+		fmt.Printf("%s %s\n", green("caught variants:"), strings.Join(variants, ", "))
+	}
+	if text := flavorText(species, DexLanguage); text != "" {
+		fmt.Println(text)
+	} else {
+		fmt.Printf("There is no entry in %s\n", DexLanguage)
+	}
+	return nil
+}
+
+func commandDex(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) > 0 && fields[0] == "language" {
+		if len(fields) < 2 {
+			fmt.Printf("Dex language: %s\n", yellow(DexLanguage))
+			return nil
+		}
+		DexLanguage = fields[1]
+		fmt.Printf("Dex language set to %s\n", yellow(DexLanguage))
+		return nil
+	}
+	if args != "" {
+		name := args
+		if key, ok := idOrNickname(args); ok {
+			name = speciesName(PokeDex[key])
+		}
+		return printDexEntry(name)
+	}
+	var national RegionalDex
+	err := getJSON("https://pokeapi.co/api/v2/pokedex/national", &national)
+	if err != nil {
+		return err
+	}
+	all := speciesOf(national)
+	seen, caught := completion(all)
+	fmt.Printf("%s seen %d, caught %d of %d (%s)\n", orange("National dex:"), seen, caught, len(all), boldGreen(fmt.Sprintf("%.1f%%", percentage(caught, len(all)))))
+	variants := countVariants()
+	fmt.Printf("%s %d shiny, %d forms, %d genders\n", orange("Variants caught:"), variants["shiny"], variants["form"], variants["gender"])
+
+	var generations NamedAPIResourceList
+	err = getJSON("https://pokeapi.co/api/v2/generation", &generations)
+	if err != nil {
+		return err
+	}
+	regions := []string{}
+	fmt.Println(orange("By generation:"))
+	for _, g := range generations.Results {
+		var generation Generation
+		err = getJSON(g.URL, &generation)
+		if err != nil {
+			return err
+		}
+		species := []string{}
+		for _, s := range generation.PokemonSpecies {
+			species = append(species, s.Name)
+		}
+		printCompletion(generation.Name, species)
+		if generation.MainRegion.URL != "" {
+			regions = append(regions, generation.MainRegion.URL)
+		}
+	}
+
+	fmt.Println(orange("By region:"))
+	for _, url := range regions {
+		var region Region
+		err = getJSON(url, &region)
+		if err != nil {
+			return err
+		}
+		if len(region.Pokedexes) == 0 {
+			continue
+		}
+		// the first pokedex of a region is its main regional dex
+		var regional RegionalDex
+		err = getJSON(region.Pokedexes[0].URL, &regional)
+		if err != nil {
+			return err
+		}
+		printCompletion(strings.TrimPrefix(region.Name, "original-"), speciesOf(regional))
+	}
+	printDexEntries(national)
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	DexSeen, DexCaught = nil, nil
+	markSeen("rattata")
+	markCaught("pidgey")
+	cases := []struct {
+		name           string
+		species        []string
+		expectedSeen   int
+		expectedCaught int
+	}{
+		{name: "mixed", species: []string{"pidgey", "rattata", "spearow"}, expectedSeen: 2, expectedCaught: 1},
+		{name: "unseen", species: []string{"spearow"}},
+		{name: "empty region", species: nil},
+	}
+	for _, c := range cases {
+		seen, caught := completion(c.species)
+		if seen != c.expectedSeen || caught != c.expectedCaught {
+			t.Errorf("%s: expected %d seen and %d caught, got %d and %d", c.name, c.expectedSeen, c.expectedCaught, seen, caught)
+		}
+	}
+	if percentage(1, 0) != 0 || percentage(1, 4) != 25 {
+		t.Errorf("expected 0%% and 25%%, got %v%% and %v%%", percentage(1, 0), percentage(1, 4))
+	}
+}
+
+func TestRegisterCaughtSpecies(t *testing.T) {
+	DexSeen, DexCaught = nil, nil
+	PokeDex = map[string]PokemonInformation{"1": {Name: "deoxys-attack", Species: NamedAPIResource{Name: "deoxys"}}}
+	registerCaughtSpecies()
+	if !DexCaught["deoxys"] || !DexSeen["deoxys"] || DexCaught["deoxys-attack"] {
+		t.Errorf("expected the species deoxys to be caught, got %v", DexCaught)
+	}
+}
+
+func TestGenderRatio(t *testing.T) {
+	cases := []struct {
+		rate     int
+		expected string
+	}{
+		{rate: -1, expected: "genderless"},
+		{rate: 0, expected: "100.0% male, 0.0% female"},
+		{rate: 1, expected: "87.5% male, 12.5% female"},
+		{rate: 8, expected: "0.0% male, 100.0% female"},
+	}
+	for _, c := range cases {
+		actual := genderRatio(c.rate)
+		if actual != c.expected {
+			t.Errorf("rate %d: expected %q, got %q", c.rate, c.expected, actual)
+		}
+	}
+}
+
+func TestSpeciesTexts(t *testing.T) {
+	var species PokemonSpecies
+	err := json.Unmarshal([]byte(`{
+		"genera": [{"genus": "Mouse Pokémon", "language": {"name": "en"}}, {"genus": "Maus", "language": {"name": "de"}}],
+		"flavor_text_entries": [
+			{"flavor_text": "old\ntext", "language": {"name": "en"}},
+			{"flavor_text": "When several of\nthese POKéMON\fgather", "language": {"name": "en"}}
+		]
+	}`), &species)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{name: "german genus", actual: genus(species, "de"), expected: "Maus"},
+		{name: "english genus as fallback", actual: genus(species, "fr"), expected: "Mouse Pokémon"},
+		{name: "latest flavor text", actual: flavorText(species, "en"), expected: "When several of these POKéMON gather"},
+		{name: "no french flavor text", actual: flavorText(species, "fr"), expected: ""},
+	}
+	for _, c := range cases {
+		if c.actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, c.actual)
+		}
+	}
+}
+
+func TestExploreMarksSpeciesSeen(t *testing.T) {
+	useTestCache(t)
+	cache.Add("https://pokeapi.co/api/v2/location-area/sky-pillar-apex/", []byte(`{"name": "sky-pillar-apex", "location": {"name": "sky-pillar", "url": "https://pokeapi.co/api/v2/location/sky-pillar/"},
+		"pokemon_encounters": [{"pokemon": {"name": "deoxys-normal", "url": "https://pokeapi.co/api/v2/pokemon/386/"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/location/sky-pillar/", []byte(`{"name": "sky-pillar", "region": {"name": "kanto"}}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/386/", []byte(`{"name": "deoxys-normal", "species": {"name": "deoxys"}}`))
+	DexSeen, DexCaught, Badges = nil, nil, nil
+	err := commandExplore(nil, "sky-pillar-apex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !DexSeen["deoxys"] || DexSeen["deoxys-normal"] {
+		t.Errorf("expected the species deoxys to be seen, got %v", DexSeen)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// spreadMultiplier is the damage percentage of a move that hits more than one pokemon.
+const spreadMultiplier = 75
+
+// choosesTarget reports whether the user picks which pokemon a move hits, based on the PokeAPI move target.
+func choosesTarget(move Move) bool {
+	switch move.Target.Name {
+	case "", "selected-pokemon", "selected-pokemon-me-first":
+		return true
+	}
+	return false
+}
+
+func (b *battle) sideOf(target *battler) *battleSide {
+	for _, member := range b.player.members {
+		if member == target {
+			return b.player
+		}
+	}
+	return b.opponent
+}
+
+// targets returns the pokemon a move hits, a chosen target that fainted or left is replaced by another opponent.
+func (b *battle) targets(action battleAction) []*battler {
+	opponents := b.other(action.side).fighters()
+	switch action.move.Target.Name {
+	case "all-opponents":
+		return opponents
+	case "all-other-pokemon":
+		for _, ally := range action.side.fighters() {
+			if ally != action.actor {
+				opponents = append(opponents, ally)
+			}
+		}
+		return opponents
+	case "random-opponent":
+		if len(opponents) > 1 {
+			return []*battler{opponents[battleRand.Intn(len(opponents))]}
+		}
+	}
+	for _, opponent := range opponents {
+		if opponent == action.target {
+			return []*battler{opponent}
+		}
+	}
+	if len(opponents) == 0 {
+		return nil
+	}
+	return opponents[:1]
+}
+
+// weakestTarget picks the opponent closest to fainting.
+func weakestTarget(opponents []*battler) *battler {
+	weakest := opponents[0]
+	for _, opponent := range opponents[1:] {
+		if hpFraction(opponent.pokemon) < hpFraction(weakest.pokemon) {
+			weakest = opponent
+		}
+	}
+	return weakest
+}
+
+// chooseTarget asks which opponent a single target move hits when more than one is in battle.
+func (c consolePlayer) chooseTarget(b *battle, side *battleSide, move Move) *battler {
+	opponents := b.other(side).fighters()
+	if len(opponents) < 2 || !choosesTarget(move) {
+		return nil
+	}
+	for i, opponent := range opponents {
+		fmt.Printf("%s: %s %d/%d hp\n", boldYellow(i+1), yellow(opponent.pokemon.Name), opponent.pokemon.Hp, opponent.pokemon.MaxHp)
+	}
+	fmt.Printf("choose a target for %s:", cyan(move.Name))
+	for c.scanner.Scan() {
+		input := strings.TrimSpace(strings.ToLower(c.scanner.Text()))
+		for i, opponent := range opponents {
+			if input == strconv.Itoa(i+1) || input == opponent.pokemon.Name {
+				return opponent
+			}
+		}
+		fmt.Printf("choose a target for %s:", cyan(move.Name))
+	}
+	return nil
+}
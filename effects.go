@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	maxStage = 6
+	// fullParalysisChance is the percentage of turns a paralysed pokemon can't move
+	fullParalysisChance = 25
+)
+
+// stageMultiplier scales a stat by its stage from -6 to +6.
+func stageMultiplier(value, stage int) int {
+	if stage >= 0 {
+		return value * (2 + stage) / 2
+	}
+	return value * 2 / (2 - stage)
+}
+
+// effectiveSpeed is the speed used for turn order after stat stages and paralysis.
+func effectiveSpeed(b *battler) int {
+	speed := stageMultiplier(b.pokemon.Speed, b.stages["speed"])
+	if b.status == "paralysis" {
+		speed /= 2
+	}
+	return speed
+}
+
+// effectivePokemon returns a copy of the pokemon with its stat stages applied for damage calculation.
+func effectivePokemon(b *battler) PokemonInformation {
+	pokemon := b.pokemon
+	pokemon.Attack = stageMultiplier(pokemon.Attack, b.stages["attack"])
+	pokemon.Defense = stageMultiplier(pokemon.Defense, b.stages["defense"])
+	pokemon.SpecialAttack = stageMultiplier(pokemon.SpecialAttack, b.stages["special-attack"])
+	pokemon.SpecialDefense = stageMultiplier(pokemon.SpecialDefense, b.stages["special-defense"])
+	pokemon.Speed = effectiveSpeed(b)
+	return pokemon
+}
+
+func changeStage(b *battler, stat string, change int) {
+	if b.stages == nil {
+		b.stages = make(map[string]int)
+	}
+	stage := b.stages[stat] + change
+	if stage > maxStage {
+		stage = maxStage
+	}
+	if stage < -maxStage {
+		stage = -maxStage
+	}
+	if stage == b.stages[stat] {
+		fmt.Printf("%s's %s won't go any further!\n", yellow(b.pokemon.Name), stat)
+		return
+	}
+	b.stages[stat] = stage
+	if change > 0 {
+		fmt.Printf("%s's %s rose!\n", yellow(b.pokemon.Name), stat)
+	} else {
+		fmt.Printf("%s's %s fell!\n", yellow(b.pokemon.Name), stat)
+	}
+}
+
+// canMove checks whether the pokemon's status lets it act this turn.
+func canMove(b *battler) bool {
+	if b.status == "paralysis" && battleRand.Intn(100) < fullParalysisChance {
+		fmt.Printf("%s is paralyzed! It can't move!\n", yellow(b.pokemon.Name))
+		return false
+	}
+	return true
+}
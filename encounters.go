@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type EncounterDetail struct {
+	MinLevel int              `json:"min_level"`
+	MaxLevel int              `json:"max_level"`
+	Chance   int              `json:"chance"`
+	Method   NamedAPIResource `json:"method"`
+}
+
+type VersionEncounterDetail struct {
+	Version          NamedAPIResource  `json:"version"`
+	MaxChance        int               `json:"max_chance"`
+	EncounterDetails []EncounterDetail `json:"encounter_details"`
+}
+
+// encounterSlot is one pokemon of an area's encounter table for a method.
+type encounterSlot struct {
+	name     string
+	chance   int
+	minLevel int
+	maxLevel int
+}
+
+// encounterTable weighs every pokemon of the area that can be met with the method.
+// The API lists the slots per game version, the version with the best odds counts.
+func encounterTable(area LocationArea, method string) []encounterSlot {
+	table := []encounterSlot{}
+	for _, encounter := range area.PokemonEncounters {
+		best := encounterSlot{name: encounter.Pokemon.Name}
+		for _, version := range encounter.VersionDetails {
+			slot := encounterSlot{name: encounter.Pokemon.Name}
+			for _, detail := range version.EncounterDetails {
+				if detail.Method.Name != method {
+					continue
+				}
+				if slot.chance == 0 || detail.MinLevel < slot.minLevel {
+					slot.minLevel = detail.MinLevel
+				}
+				slot.maxLevel = max(slot.maxLevel, detail.MaxLevel)
+				slot.chance += detail.Chance
+			}
+			if slot.chance > best.chance {
+				best = slot
+			}
+		}
+		if best.chance > 0 {
+			table = append(table, best)
+		}
+	}
+	return table
+}
+
+// encounterMethods lists the ways pokemon can be met in the area, like walk, surf or old-rod.
+func encounterMethods(area LocationArea) []string {
+	found := map[string]bool{}
+	for _, encounter := range area.PokemonEncounters {
+		for _, version := range encounter.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				found[detail.Method.Name] = true
+			}
+		}
+	}
+	methods := []string{}
+	for method := range found {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// pickEncounter chooses a slot with a chance proportional to its weight.
+func pickEncounter(table []encounterSlot, intn func(int) int) encounterSlot {
+	total := 0
+	for _, slot := range table {
+		total += slot.chance
+	}
+	roll := intn(total)
+	for _, slot := range table {
+		if roll < slot.chance {
+			return slot
+		}
+		roll -= slot.chance
+	}
+	return table[len(table)-1]
+}
+
+func rollLevel(slot encounterSlot, intn func(int) int) int {
+	low := max(slot.minLevel, 1)
+	high := max(slot.maxLevel, low)
+	return low + intn(high-low+1)
+}
+
+// parseFindArgs splits find arguments into the area and the encounter method, walking is the default.
+func parseFindArgs(args string) (string, string, error) {
+	fields := strings.Fields(args)
+	area, method := "", "walk"
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "--method" {
+			if i+1 >= len(fields) {
+				return "", "", fmt.Errorf("usage: find <area> [--method walk|surf|old-rod|good-rod|super-rod]")
+			}
+			method = fields[i+1]
+			i++
+		} else {
+			area = fields[i]
+		}
+	}
+	return area, method, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+const areaJSON = `{"pokemon_encounters": [
+	{"pokemon": {"name": "tentacool"}, "version_details": [
+		{"version": {"name": "red"}, "encounter_details": [{"min_level": 5, "max_level": 10, "chance": 60, "method": {"name": "surf"}}]},
+		{"version": {"name": "blue"}, "encounter_details": [{"min_level": 5, "max_level": 40, "chance": 90, "method": {"name": "surf"}}]}
+	]},
+	{"pokemon": {"name": "magikarp"}, "version_details": [
+		{"version": {"name": "red"}, "encounter_details": [
+			{"min_level": 5, "max_level": 5, "chance": 100, "method": {"name": "old-rod"}},
+			{"min_level": 10, "max_level": 10, "chance": 30, "method": {"name": "surf"}},
+			{"min_level": 15, "max_level": 20, "chance": 10, "method": {"name": "surf"}}
+		]}
+	]}
+]}`
+
+func TestEncounterTable(t *testing.T) {
+	var area LocationArea
+	if err := json.Unmarshal([]byte(areaJSON), &area); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		method   string
+		expected []encounterSlot
+	}{
+		{method: "surf", expected: []encounterSlot{{"tentacool", 90, 5, 40}, {"magikarp", 40, 10, 20}}},
+		{method: "old-rod", expected: []encounterSlot{{"magikarp", 100, 5, 5}}},
+		{method: "walk", expected: []encounterSlot{}},
+	}
+	for _, c := range cases {
+		actual := encounterTable(area, c.method)
+		if !slices.Equal(actual, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.method, c.expected, actual)
+		}
+	}
+	expected := []string{"old-rod", "surf"}
+	if actual := encounterMethods(area); !slices.Equal(actual, expected) {
+		t.Errorf("expected methods %v, got %v", expected, actual)
+	}
+}
+
+func TestPickEncounter(t *testing.T) {
+	table := []encounterSlot{{name: "tentacool", chance: 90}, {name: "magikarp", chance: 40}}
+	cases := []struct {
+		roll     int
+		expected string
+	}{
+		{roll: 0, expected: "tentacool"},
+		{roll: 89, expected: "tentacool"},
+		{roll: 90, expected: "magikarp"},
+		{roll: 129, expected: "magikarp"},
+	}
+	for _, c := range cases {
+		actual := pickEncounter(table, fixed(c.roll))
+		if actual.name != c.expected {
+			t.Errorf("roll %d: expected %s, got %s", c.roll, c.expected, actual.name)
+		}
+	}
+	slot := encounterSlot{minLevel: 15, maxLevel: 20}
+	if low, high := rollLevel(slot, fixed(0)), rollLevel(slot, func(n int) int { return n - 1 }); low != 15 || high != 20 {
+		t.Errorf("expected levels from 15 to 20, got %d to %d", low, high)
+	}
+}
+
+func TestParseFindArgs(t *testing.T) {
+	area, method, err := parseFindArgs("route-1 --method old-rod")
+	if err != nil || area != "route-1" || method != "old-rod" {
+		t.Errorf("expected route-1 and old-rod, got %s and %s (%v)", area, method, err)
+	}
+	if _, method, _ := parseFindArgs("route-1"); method != "walk" {
+		t.Errorf("expected walking by default, got %s", method)
+	}
+	if _, _, err := parseFindArgs("route-1 --method"); err == nil {
+		t.Error("expected an error without a method")
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type EvolutionChain struct {
+	Chain ChainLink `json:"chain"`
+}
+
+type ChainLink struct {
+	Species          NamedAPIResource  `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+type EvolutionDetail struct {
+	Trigger  NamedAPIResource `json:"trigger"`
+	MinLevel int              `json:"min_level"`
+	Item     NamedAPIResource `json:"item"`
+	HeldItem NamedAPIResource `json:"held_item"`
+}
+
+// evolutionTrigger describes what just happened to a pokemon, kind uses the PokeAPI trigger names.
+type evolutionTrigger struct {
+	kind string // "level-up", "use-item" or "trade"
+	item string
+}
+
+func getEvolutionChain(pokemon PokemonInformation) (EvolutionChain, error) {
+	species, err := getSpecies(pokemon)
+	if err != nil {
+		return EvolutionChain{}, err
+	}
+	data, err := GetData(cache, species.EvolutionChain.URL)
+	if err != nil {
+		return EvolutionChain{}, err
+	}
+	var chain EvolutionChain
+	err = json.Unmarshal(data, &chain)
+	if err != nil {
+		return EvolutionChain{}, err
+	}
+	return chain, nil
+}
+
+func findChainLink(link ChainLink, species string) (ChainLink, bool) {
+	if link.Species.Name == species {
+		return link, true
+	}
+	for _, next := range link.EvolvesTo {
+		if found, ok := findChainLink(next, species); ok {
+			return found, true
+		}
+	}
+	return ChainLink{}, false
+}
+
+func evolutionConditionMet(detail EvolutionDetail, pokemon PokemonInformation, trigger evolutionTrigger) bool {
+	if detail.Trigger.Name != trigger.kind {
+		return false
+	}
+	switch trigger.kind {
+	case "level-up":
+		// friendship, time of day and other level-up conditions are not tracked yet
+		return detail.MinLevel > 0 && pokemon.Level >= detail.MinLevel
+	case "use-item":
+		return detail.Item.Name == trigger.item
+	case "trade":
+		return detail.HeldItem.Name == "" || detail.HeldItem.Name == trigger.item
+	}
+	return false
+}
+
+// evolutionTargets returns the species the pokemon can evolve into after the trigger.
+func evolutionTargets(pokemon PokemonInformation, chain EvolutionChain, trigger evolutionTrigger) []string {
+	speciesName := pokemon.Species.Name
+	if speciesName == "" {
+		speciesName = pokemon.Name
+	}
+	link, ok := findChainLink(chain.Chain, speciesName)
+	if !ok {
+		return nil
+	}
+	targets := []string{}
+	for _, next := range link.EvolvesTo {
+		for _, detail := range next.EvolutionDetails {
+			if evolutionConditionMet(detail, pokemon, trigger) {
+				targets = append(targets, next.Species.Name)
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// evolvedForm returns the /pokemon name the pokemon evolves into. A form like rattata-alola keeps its
+// suffix when the evolved species has the same variety, otherwise it becomes the default form.
+func evolvedForm(pokemon PokemonInformation, species string) (string, error) {
+	suffix, ok := strings.CutPrefix(pokemon.Name, speciesName(pokemon)+"-")
+	if !ok {
+		return species, nil
+	}
+	var evolved PokemonSpecies
+	err := getJSON("https://pokeapi.co/api/v2/pokemon-species/"+species, &evolved)
+	if err != nil {
+		return "", err
+	}
+	for _, variety := range evolved.Varieties {
+		if variety.Pokemon.Name == species+"-"+suffix {
+			return variety.Pokemon.Name, nil
+		}
+	}
+	return species, nil
+}
+
+// evolve builds the evolved pokemon while keeping what the player trained.
+func evolve(pokemon PokemonInformation, species string) (PokemonInformation, error) {
+	form, err := evolvedForm(pokemon, species)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	data, err := GetData(cache, "https://pokeapi.co/api/v2/pokemon/"+form)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	var evolved PokemonInformation
+	err = json.Unmarshal(data, &evolved)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	evolved.Level = pokemon.Level
+	evolved.Experience = pokemon.Experience
+	evolved.GrowthRate = pokemon.GrowthRate
+	evolved.Moves = pokemon.Moves
+	evolved.IVs = pokemon.IVs
+	evolved.EVs = pokemon.EVs
+	evolved.Nature = pokemon.Nature
+	evolved.Ability = evolvedAbility(pokemon, evolved)
+	evolved.HeldItem = pokemon.HeldItem
+	evolved.ID = pokemon.ID
+	evolved.Nickname = pokemon.Nickname
+	evolved.Gender = pokemon.Gender
+	evolved.Shiny = pokemon.Shiny
+	resetStats(&evolved)
+	return evolved, nil
+}
+
+// evolvedAbility keeps the ability slot of the pokemon, like evolving does in the games.
+func evolvedAbility(pokemon, evolved PokemonInformation) string {
+	for _, old := range pokemon.Abilities {
+		if old.Ability.Name != pokemon.Ability {
+			continue
+		}
+		for _, a := range evolved.Abilities {
+			if a.Slot == old.Slot {
+				return a.Ability.Name
+			}
+		}
+	}
+	return chooseAbility(evolved)
+}
+
+// offerEvolution asks the player whether the caught pokemon should evolve when the trigger allows it
+// and reports whether it evolved.
+func offerEvolution(key string, trigger evolutionTrigger) (bool, error) {
+	pokemon, ok := PokeDex[key]
+	if !ok {
+		return false, nil
+	}
+	chain, err := getEvolutionChain(pokemon)
+	if err != nil {
+		return false, err
+	}
+	targets := evolutionTargets(pokemon, chain, trigger)
+	if len(targets) == 0 {
+		return false, nil
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for _, target := range targets {
+		fmt.Printf("What? %s is evolving into %s!\nDo you want to evolve? (y/n):", yellow(pokemon.Name), boldGreen(target))
+		if !scanner.Scan() || strings.TrimSpace(strings.ToLower(scanner.Text())) != "y" {
+			fmt.Printf("%s did not evolve\n", yellow(pokemon.Name))
+			continue
+		}
+		evolved, err := evolve(pokemon, target)
+		if err != nil {
+			return false, err
+		}
+		if trigger.kind == "trade" && trigger.item != "" && tradeConsumesItem(chain, target, trigger.item) {
+			evolved.HeldItem = ""
+		}
+		PokeDex[key] = evolved
+		markCaught(speciesName(evolved))
+		markVariants(evolved)
+		fmt.Printf("Congratulations! Your %s evolved into %s!\n", yellow(pokemon.Name), boldGreen(evolved.Name))
+		return true, nil
+	}
+	return false, nil
+}
+
+// tradeConsumesItem reports whether the evolution into target needed the held item, which is used up by it.
+func tradeConsumesItem(chain EvolutionChain, target, item string) bool {
+	link, ok := findChainLink(chain.Chain, target)
+	if !ok {
+		return false
+	}
+	for _, detail := range link.EvolutionDetails {
+		if detail.Trigger.Name == "trade" && detail.HeldItem.Name == item {
+			return true
+		}
+	}
+	return false
+}
+
+// commandTrade sends a pokemon through a link trade and back, which is what trade evolutions wait for.
+func commandTrade(_ *Config, ref string) error {
+	if ref == "" {
+		return fmt.Errorf("usage: trade <pokemon>")
+	}
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	pokemon := PokeDex[key]
+	fmt.Printf("%s was traded away and sent back\n", yellow(displayName(pokemon)))
+	evolved, err := offerEvolution(key, evolutionTrigger{kind: "trade", item: pokemon.HeldItem})
+	if err != nil {
+		return err
+	}
+	if !evolved {
+		fmt.Printf("Nothing happened to %s\n", yellow(displayName(pokemon)))
+	}
+	return nil
+}
+
+func describeEvolution(details []EvolutionDetail) string {
+	conditions := []string{}
+	for _, detail := range details {
+		switch {
+		case detail.Trigger.Name == "level-up" && detail.MinLevel > 0:
+			conditions = append(conditions, fmt.Sprintf("level %d", detail.MinLevel))
+		case detail.Trigger.Name == "use-item":
+			conditions = append(conditions, "use "+detail.Item.Name)
+		case detail.Trigger.Name == "trade" && detail.HeldItem.Name != "":
+			conditions = append(conditions, "trade holding "+detail.HeldItem.Name)
+		default:
+			conditions = append(conditions, detail.Trigger.Name)
+		}
+	}
+	return strings.Join(conditions, " or ")
+}
+
+func printChainLink(link ChainLink, indent string) {
+	for _, next := range link.EvolvesTo {
+		fmt.Printf("%s└─ %s (%s)\n", indent, yellow(next.Species.Name), cyan(describeEvolution(next.EvolutionDetails)))
+		printChainLink(next, indent+"   ")
+	}
+}
+
+func commandEvolutions(_ *Config, pokemonName string) error {
+	// every pokemon of a species shares the chain, so anything but an ID or nickname is a species name
+	pokemon := PokemonInformation{Name: pokemonName}
+	if key, ok := idOrNickname(pokemonName); ok {
+		pokemon = PokeDex[key]
+	}
+	chain, err := getEvolutionChain(pokemon)
+	if err != nil {
+		return err
+	}
+	fmt.Println(yellow(chain.Chain.Species.Name))
+	printChainLink(chain.Chain, "")
+	return nil
+}
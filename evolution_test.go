@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func evolvesTo(species string, details ...EvolutionDetail) ChainLink {
+	return ChainLink{Species: NamedAPIResource{Name: species}, EvolutionDetails: details}
+}
+
+func TestEvolutionConditionMet(t *testing.T) {
+	levelUp := NamedAPIResource{Name: "level-up"}
+	useItem := NamedAPIResource{Name: "use-item"}
+	trade := NamedAPIResource{Name: "trade"}
+	cases := []struct {
+		name     string
+		detail   EvolutionDetail
+		level    int
+		trigger  evolutionTrigger
+		expected bool
+	}{
+		{name: "below level", detail: EvolutionDetail{Trigger: levelUp, MinLevel: 16}, level: 15, trigger: evolutionTrigger{kind: "level-up"}, expected: false},
+		{name: "at level", detail: EvolutionDetail{Trigger: levelUp, MinLevel: 16}, level: 16, trigger: evolutionTrigger{kind: "level-up"}, expected: true},
+		{name: "friendship level-up", detail: EvolutionDetail{Trigger: levelUp}, level: 50, trigger: evolutionTrigger{kind: "level-up"}, expected: false},
+		{name: "matching stone", detail: EvolutionDetail{Trigger: useItem, Item: NamedAPIResource{Name: "fire-stone"}}, trigger: evolutionTrigger{kind: "use-item", item: "fire-stone"}, expected: true},
+		{name: "other stone", detail: EvolutionDetail{Trigger: useItem, Item: NamedAPIResource{Name: "fire-stone"}}, trigger: evolutionTrigger{kind: "use-item", item: "water-stone"}, expected: false},
+		{name: "stone on level-up", detail: EvolutionDetail{Trigger: useItem, Item: NamedAPIResource{Name: "fire-stone"}}, level: 100, trigger: evolutionTrigger{kind: "level-up"}, expected: false},
+		{name: "plain trade", detail: EvolutionDetail{Trigger: trade}, trigger: evolutionTrigger{kind: "trade", item: "potion"}, expected: true},
+		{name: "trade holding item", detail: EvolutionDetail{Trigger: trade, HeldItem: NamedAPIResource{Name: "metal-coat"}}, trigger: evolutionTrigger{kind: "trade", item: "metal-coat"}, expected: true},
+		{name: "trade without item", detail: EvolutionDetail{Trigger: trade, HeldItem: NamedAPIResource{Name: "metal-coat"}}, trigger: evolutionTrigger{kind: "trade"}, expected: false},
+	}
+	for _, c := range cases {
+		actual := evolutionConditionMet(c.detail, PokemonInformation{Level: c.level}, c.trigger)
+		if actual != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestEvolutionTargets(t *testing.T) {
+	eevee := EvolutionChain{Chain: ChainLink{Species: NamedAPIResource{Name: "eevee"}, EvolvesTo: []ChainLink{
+		evolvesTo("vaporeon", EvolutionDetail{Trigger: NamedAPIResource{Name: "use-item"}, Item: NamedAPIResource{Name: "water-stone"}}),
+		evolvesTo("jolteon", EvolutionDetail{Trigger: NamedAPIResource{Name: "use-item"}, Item: NamedAPIResource{Name: "thunder-stone"}}),
+		evolvesTo("espeon", EvolutionDetail{Trigger: NamedAPIResource{Name: "level-up"}}),
+	}}}
+	onix := EvolutionChain{Chain: ChainLink{Species: NamedAPIResource{Name: "onix"}, EvolvesTo: []ChainLink{
+		evolvesTo("steelix", EvolutionDetail{Trigger: NamedAPIResource{Name: "trade"}, HeldItem: NamedAPIResource{Name: "metal-coat"}}),
+	}}}
+	charmander := EvolutionChain{Chain: ChainLink{Species: NamedAPIResource{Name: "charmander"}, EvolvesTo: []ChainLink{
+		{Species: NamedAPIResource{Name: "charmeleon"}, EvolutionDetails: []EvolutionDetail{{Trigger: NamedAPIResource{Name: "level-up"}, MinLevel: 16}}, EvolvesTo: []ChainLink{
+			evolvesTo("charizard", EvolutionDetail{Trigger: NamedAPIResource{Name: "level-up"}, MinLevel: 36}),
+		}},
+	}}}
+	cases := []struct {
+		name     string
+		pokemon  PokemonInformation
+		chain    EvolutionChain
+		trigger  evolutionTrigger
+		expected []string
+	}{
+		{name: "branch by stone", pokemon: PokemonInformation{Name: "eevee"}, chain: eevee, trigger: evolutionTrigger{kind: "use-item", item: "thunder-stone"}, expected: []string{"jolteon"}},
+		{name: "branch without match", pokemon: PokemonInformation{Name: "eevee", Level: 40}, chain: eevee, trigger: evolutionTrigger{kind: "level-up"}, expected: []string{}},
+		{name: "trade with metal coat", pokemon: PokemonInformation{Name: "onix"}, chain: onix, trigger: evolutionTrigger{kind: "trade", item: "metal-coat"}, expected: []string{"steelix"}},
+		{name: "trade without metal coat", pokemon: PokemonInformation{Name: "onix"}, chain: onix, trigger: evolutionTrigger{kind: "trade"}, expected: []string{}},
+		{name: "middle of the chain", pokemon: PokemonInformation{Name: "charmeleon", Level: 36}, chain: charmander, trigger: evolutionTrigger{kind: "level-up"}, expected: []string{"charizard"}},
+		{name: "last stage", pokemon: PokemonInformation{Name: "charizard", Level: 100}, chain: charmander, trigger: evolutionTrigger{kind: "level-up"}, expected: nil},
+	}
+	for _, c := range cases {
+		actual := evolutionTargets(c.pokemon, c.chain, c.trigger)
+		if !slices.Equal(actual, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, actual)
+		}
+	}
+	if !tradeConsumesItem(onix, "steelix", "metal-coat") || tradeConsumesItem(onix, "steelix", "potion") {
+		t.Error("expected only the metal coat to be used up by evolving into steelix")
+	}
+}
+
+func TestEvolveKeepsForm(t *testing.T) {
+	useTestCache(t)
+	cachePokemon("raticate", []string{"normal"})
+	cachePokemon("raticate-alola", []string{"dark", "normal"})
+	cachePokemon("pikachu", []string{"electric"})
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/raticate", []byte(`{"name": "raticate", "varieties": [
+		{"is_default": true, "pokemon": {"name": "raticate"}},
+		{"is_default": false, "pokemon": {"name": "raticate-alola"}},
+		{"is_default": false, "pokemon": {"name": "raticate-totem-alola"}}
+	]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/pikachu", []byte(`{"name": "pikachu", "varieties": [{"is_default": true, "pokemon": {"name": "pikachu"}}]}`))
+	cases := []struct {
+		name          string
+		pokemon       PokemonInformation
+		species       string
+		expected      string
+		expectedTypes int
+	}{
+		{name: "default form", pokemon: PokemonInformation{Name: "rattata", Species: NamedAPIResource{Name: "rattata"}}, species: "raticate", expected: "raticate", expectedTypes: 1},
+		{name: "alolan form", pokemon: PokemonInformation{Name: "rattata-alola", Species: NamedAPIResource{Name: "rattata"}}, species: "raticate", expected: "raticate-alola", expectedTypes: 2},
+		{name: "form without evolved variety", pokemon: PokemonInformation{Name: "pichu-spiky-eared", Species: NamedAPIResource{Name: "pichu"}}, species: "pikachu", expected: "pikachu", expectedTypes: 1},
+	}
+	for _, c := range cases {
+		c.pokemon.Level = 20
+		evolved, err := evolve(c.pokemon, c.species)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if evolved.Name != c.expected || len(evolved.Types) != c.expectedTypes {
+			t.Errorf("%s: expected %s with %d types, got %s with %v", c.name, c.expected, c.expectedTypes, evolved.Name, evolved.Types)
+		}
+	}
+}
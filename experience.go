@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const maxLevel = 100
+
+type PokemonSpecies struct {
+	Name           string           `json:"name"`
+	GrowthRate     NamedAPIResource `json:"growth_rate"`
+	CaptureRate    int              `json:"capture_rate"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Genera []struct {
+		Genus    string           `json:"genus"`
+		Language NamedAPIResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string           `json:"flavor_text"`
+		Language   NamedAPIResource `json:"language"`
+	} `json:"flavor_text_entries"`
+	Habitat     NamedAPIResource   `json:"habitat"`
+	Shape       NamedAPIResource   `json:"shape"`
+	Color       NamedAPIResource   `json:"color"`
+	EggGroups   []NamedAPIResource `json:"egg_groups"`
+	GenderRate  int                `json:"gender_rate"`
+	IsLegendary bool               `json:"is_legendary"`
+	IsMythical  bool               `json:"is_mythical"`
+	Varieties   []SpeciesVariety   `json:"varieties"`
+}
+
+type GrowthRate struct {
+	Name   string `json:"name"`
+	Levels []struct {
+		Level      int `json:"level"`
+		Experience int `json:"experience"`
+	} `json:"levels"`
+}
+
+func getSpecies(pokemon PokemonInformation) (PokemonSpecies, error) {
+	url := pokemon.Species.URL
+	if url == "" {
+		// saves made before the species was stored only know the pokemon name
+		url = "https://pokeapi.co/api/v2/pokemon-species/" + pokemon.Name
+	}
+	data, err := GetData(cache, url)
+	if err != nil {
+		return PokemonSpecies{}, err
+	}
+	var species PokemonSpecies
+	err = json.Unmarshal(data, &species)
+	if err != nil {
+		return PokemonSpecies{}, err
+	}
+	return species, nil
+}
+
+func getGrowthRate(pokemon PokemonInformation) (GrowthRate, error) {
+	url := "https://pokeapi.co/api/v2/growth-rate/" + pokemon.GrowthRate
+	if pokemon.GrowthRate == "" {
+		species, err := getSpecies(pokemon)
+		if err != nil {
+			return GrowthRate{}, err
+		}
+		url = species.GrowthRate.URL
+	}
+	data, err := GetData(cache, url)
+	if err != nil {
+		return GrowthRate{}, err
+	}
+	var rate GrowthRate
+	err = json.Unmarshal(data, &rate)
+	if err != nil {
+		return GrowthRate{}, err
+	}
+	return rate, nil
+}
+
+// experienceForLevel returns the total experience needed to reach a level on the given curve.
+func experienceForLevel(rate GrowthRate, level int) int {
+	for _, l := range rate.Levels {
+		if l.Level == level {
+			return l.Experience
+		}
+	}
+	return 0
+}
+
+// levelForExperience returns the highest level the total experience reaches on the given curve.
+func levelForExperience(rate GrowthRate, experience int) int {
+	level := 1
+	for _, l := range rate.Levels {
+		if l.Experience <= experience && l.Level > level {
+			level = l.Level
+		}
+	}
+	return level
+}
+
+// experienceGain uses the classic formula for defeating a wild pokemon.
+func experienceGain(defeated PokemonInformation) int {
+	return defeated.BaseExperience * defeated.Level / 7
+}
+
+// gainExperience adds experience, levels the pokemon up along its growth curve and recomputes its stats.
+func gainExperience(pokemon *PokemonInformation, experience int) error {
+	rate, err := getGrowthRate(*pokemon)
+	if err != nil {
+		return err
+	}
+	pokemon.GrowthRate = rate.Name
+	if floor := experienceForLevel(rate, pokemon.Level); pokemon.Experience < floor {
+		// older saves levelled up without tracking experience
+		pokemon.Experience = floor
+	}
+	levelLimit := levelCap()
+	if pokemon.Level >= levelLimit {
+		fmt.Printf("%s can't grow past level %d until you earn more badges\n", yellow(pokemon.Name), levelLimit)
+		return nil
+	}
+	pokemon.Experience += experience
+	if maxExperience := experienceForLevel(rate, levelLimit); maxExperience > 0 && pokemon.Experience > maxExperience {
+		pokemon.Experience = maxExperience
+	}
+	fmt.Printf("%s gained %s experience points\n", yellow(pokemon.Name), boldYellow(experience))
+	newLevel := levelForExperience(rate, pokemon.Experience)
+	for pokemon.Level < newLevel {
+		pokemon.Level++
+		fmt.Printf("%s grew to level %s!\n", yellow(pokemon.Name), boldGreen(pokemon.Level))
+	}
+	computeStats(pokemon)
+	return nil
+}
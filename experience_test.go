@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLevelForExperience(t *testing.T) {
+	var rate GrowthRate
+	for level, experience := range []int{0, 8, 27, 64, 125} {
+		rate.Levels = append(rate.Levels, struct {
+			Level      int `json:"level"`
+			Experience int `json:"experience"`
+		}{Level: level + 1, Experience: experience})
+	}
+	cases := []struct {
+		experience int
+		expected   int
+	}{
+		{experience: 0, expected: 1},
+		{experience: 7, expected: 1},
+		{experience: 8, expected: 2},
+		{experience: 100, expected: 4},
+		{experience: 1000, expected: 5},
+	}
+	for _, c := range cases {
+		actual := levelForExperience(rate, c.experience)
+		if actual != c.expected {
+			t.Errorf("experience %d: expected level %d, got %d", c.experience, c.expected, actual)
+		}
+	}
+}
+
+// cacheGrowthRate caches a medium-fast curve, where each level needs its cube in experience, up to level 30.
+func cacheGrowthRate() {
+	levels := []string{}
+	for level := 1; level <= 30; level++ {
+		levels = append(levels, fmt.Sprintf(`{"level": %d, "experience": %d}`, level, level*level*level))
+	}
+	cache.Add("https://pokeapi.co/api/v2/growth-rate/medium", []byte(`{"name": "medium", "levels": [`+strings.Join(levels, ", ")+`]}`))
+}
+
+func TestGainExperience(t *testing.T) {
+	useTestCache(t)
+	cacheGrowthRate()
+	cachePokemon("eevee", []string{"normal"})
+	var base PokemonInformation
+	if err := getJSON("https://pokeapi.co/api/v2/pokemon/eevee", &base); err != nil {
+		t.Fatal(err)
+	}
+	Badges = nil
+	cases := []struct {
+		name               string
+		level              int
+		gain               int
+		expectedLevel      int
+		expectedExperience int
+	}{
+		{name: "several levels at once", level: 5, gain: 400, expectedLevel: 8, expectedExperience: 525},
+		{name: "clamped to the level cap", level: 18, gain: 100000, expectedLevel: 20, expectedExperience: 8000},
+		{name: "already at the level cap", level: 20, gain: 100, expectedLevel: 20, expectedExperience: 8000},
+	}
+	for _, c := range cases {
+		pokemon := base
+		pokemon.Level, pokemon.GrowthRate = c.level, "medium"
+		computeStats(&pokemon)
+		err := gainExperience(&pokemon, c.gain)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if pokemon.Level != c.expectedLevel || pokemon.Experience != c.expectedExperience {
+			t.Errorf("%s: expected level %d with %d experience, got level %d with %d", c.name, c.expectedLevel, c.expectedExperience, pokemon.Level, pokemon.Experience)
+		}
+		expected := base
+		expected.Level = c.expectedLevel
+		computeStats(&expected)
+		if pokemon.Attack != expected.Attack || pokemon.MaxHp != expected.MaxHp {
+			t.Errorf("%s: expected the stats of level %d, got attack %d and max hp %d", c.name, c.expectedLevel, pokemon.Attack, pokemon.MaxHp)
+		}
+	}
+}
+
+func TestWriteBackSavesExperience(t *testing.T) {
+	useTestCache(t)
+	cacheGrowthRate()
+	cachePokemon("eevee", []string{"normal"})
+	var pokemon PokemonInformation
+	if err := getJSON("https://pokeapi.co/api/v2/pokemon/eevee", &pokemon); err != nil {
+		t.Fatal(err)
+	}
+	pokemon.Level, pokemon.GrowthRate, pokemon.Experience = 5, "medium", 125
+	PokeDex, Badges = map[string]PokemonInformation{"1": pokemon}, nil
+	defer func() { PokeDex = nil }()
+
+	trained := &battler{key: "1", pokemon: pokemon}
+	wild := &battler{pokemon: PokemonInformation{Name: "pidgey"}}
+	side := &battleSide{members: []*battler{trained, wild}}
+	if err := gainExperience(&trained.pokemon, 400); err != nil {
+		t.Fatal(err)
+	}
+	trained.pokemon.Hp = 1
+	side.writeBack()
+
+	saved := PokeDex["1"]
+	if saved.Level != 8 || saved.Experience != 525 {
+		t.Errorf("expected level 8 with 525 experience to be saved, got level %d with %d", saved.Level, saved.Experience)
+	}
+	if saved.Hp != saved.MaxHp {
+		t.Errorf("expected the saved pokemon to be healed, got %d of %d hp", saved.Hp, saved.MaxHp)
+	}
+	if len(PokeDex) != 1 {
+		t.Errorf("expected only party pokemon to be saved, got %d pokemon", len(PokeDex))
+	}
+}
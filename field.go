@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+)
+
+// fieldTurns is how many turns weather and terrain last after a move sets them.
+const fieldTurns = 5
+
+// fieldState is the weather and terrain of a battle, both last until their turns run out.
+type fieldState struct {
+	weather      string
+	weatherTurns int
+	terrain      string
+	terrainTurns int
+}
+
+var weatherMoves = map[string]string{
+	"rain-dance": "rain",
+	"sunny-day":  "sun",
+	"sandstorm":  "sandstorm",
+	"hail":       "hail",
+}
+
+var terrainMoves = map[string]string{
+	"electric-terrain": "electric",
+	"grassy-terrain":   "grassy",
+	"misty-terrain":    "misty",
+	"psychic-terrain":  "psychic",
+}
+
+type fieldText struct {
+	start  string
+	active string
+	end    string
+}
+
+var fieldTexts = map[string]fieldText{
+	"rain":      {start: "It started to rain!", active: "It is raining", end: "The rain stopped."},
+	"sun":       {start: "The sunlight turned harsh!", active: "The sunlight is harsh", end: "The harsh sunlight faded."},
+	"sandstorm": {start: "A sandstorm kicked up!", active: "A sandstorm is raging", end: "The sandstorm subsided."},
+	"hail":      {start: "It started to hail!", active: "It is hailing", end: "The hail stopped."},
+	"electric":  {start: "An electric current ran across the battlefield!", active: "Electric terrain", end: "The electricity disappeared from the battlefield."},
+	"grassy":    {start: "Grass grew to cover the battlefield!", active: "Grassy terrain", end: "The grass disappeared from the battlefield."},
+	"misty":     {start: "Mist swirled around the battlefield!", active: "Misty terrain", end: "The mist disappeared from the battlefield."},
+	"psychic":   {start: "The battlefield got weird!", active: "Psychic terrain", end: "The weirdness disappeared from the battlefield."},
+}
+
+// terrainBoosts maps each terrain to the move type it powers up for grounded attackers.
+var terrainBoosts = map[string]string{
+	"electric": "electric",
+	"grassy":   "grass",
+	"psychic":  "psychic",
+}
+
+func fieldMove(move Move) bool {
+	_, weather := weatherMoves[move.Name]
+	_, terrain := terrainMoves[move.Name]
+	return weather || terrain
+}
+
+// grounded reports whether terrain affects the pokemon.
+func grounded(pokemon PokemonInformation) bool {
+	return !hasType(pokemon, "flying") && pokemon.Ability != "levitate"
+}
+
+// terrainPower returns the move's power after the terrain, boosted moves get 1.3 times the power.
+func terrainPower(attacker, defender PokemonInformation, move Move, terrain string) int {
+	if boosted, ok := terrainBoosts[terrain]; ok && boosted == move.Type.Name && grounded(attacker) {
+		return move.Power * 13 / 10
+	}
+	if terrain == "misty" && move.Type.Name == "dragon" && grounded(defender) {
+		return move.Power / 2
+	}
+	return move.Power
+}
+
+// weatherPercent is the damage percentage the weather gives a move of the given type.
+func weatherPercent(moveType, weather string) int {
+	switch {
+	case weather == "rain" && moveType == "water", weather == "sun" && moveType == "fire":
+		return 150
+	case weather == "rain" && moveType == "fire", weather == "sun" && moveType == "water":
+		return 50
+	}
+	return 100
+}
+
+// weatherImmune reports whether the pokemon takes no chip damage from the weather.
+func weatherImmune(pokemon PokemonInformation, weather string) bool {
+	switch weather {
+	case "sandstorm":
+		return hasType(pokemon, "rock") || hasType(pokemon, "ground") || hasType(pokemon, "steel")
+	case "hail":
+		return hasType(pokemon, "ice")
+	}
+	return true
+}
+
+// describe returns what the battle UI shows about the field, or an empty string for a plain field.
+func (f fieldState) describe() string {
+	parts := ""
+	if f.weather != "" {
+		parts = fmt.Sprintf("%s (%d turns left)", fieldTexts[f.weather].active, f.weatherTurns)
+	}
+	if f.terrain != "" {
+		if parts != "" {
+			parts += ", "
+		}
+		parts += fmt.Sprintf("%s (%d turns left)", fieldTexts[f.terrain].active, f.terrainTurns)
+	}
+	return parts
+}
+
+// conditions returns the damage roll fields that depend on the field.
+func (f fieldState) conditions(spread bool) damageRoll {
+	return damageRoll{spread: spread, weather: f.weather, terrain: f.terrain}
+}
+
+// changeField starts the weather or terrain a move sets, it reports whether the move was a field move.
+func (b *battle) changeField(move Move) bool {
+	current, turns := &b.field.weather, &b.field.weatherTurns
+	name, ok := weatherMoves[move.Name]
+	if !ok {
+		current, turns = &b.field.terrain, &b.field.terrainTurns
+		name, ok = terrainMoves[move.Name]
+	}
+	if !ok {
+		return false
+	}
+	if *current == name {
+		fmt.Println("But it failed!")
+		return true
+	}
+	*current, *turns = name, fieldTurns
+	fmt.Println(cyan(fieldTexts[name].start))
+	b.record(battleEvent{Kind: "field", Field: name})
+	return true
+}
+
+// endOfTurn deals weather chip damage, heals on grassy terrain and counts the field down.
+func (b *battle) endOfTurn() {
+	for _, side := range []*battleSide{b.player, b.opponent} {
+		for _, active := range side.fighters() {
+			pokemon := &active.pokemon
+			if !weatherImmune(*pokemon, b.field.weather) {
+				damage := max(pokemon.MaxHp/16, 1)
+				pokemon.Hp -= damage
+				fmt.Printf("%s is buffeted by the %s! %s\n", yellow(pokemon.Name), b.field.weather, red(fmt.Sprintf("-%d", damage)))
+				b.record(battleEvent{Kind: "damage", Side: b.sideName(side), Pokemon: pokemon.Name, Damage: damage, Hp: pokemon.Hp})
+				b.checkHeldItem(active)
+			}
+			if b.field.terrain == "grassy" && grounded(*pokemon) && pokemon.Hp > 0 && pokemon.Hp < pokemon.MaxHp {
+				heal := min(max(pokemon.MaxHp/16, 1), pokemon.MaxHp-pokemon.Hp)
+				pokemon.Hp += heal
+				fmt.Printf("%s restored %s hp from the grassy terrain\n", yellow(pokemon.Name), green(heal))
+				b.record(battleEvent{Kind: "heal", Side: b.sideName(side), Pokemon: pokemon.Name, Heal: heal, Hp: pokemon.Hp})
+			}
+		}
+	}
+	b.countDown(&b.field.weather, &b.field.weatherTurns)
+	b.countDown(&b.field.terrain, &b.field.terrainTurns)
+}
+
+func (b *battle) countDown(current *string, turns *int) {
+	if *current == "" {
+		return
+	}
+	*turns--
+	if *turns > 0 {
+		return
+	}
+	fmt.Println(cyan(fieldTexts[*current].end))
+	b.record(battleEvent{Kind: "field-end", Field: *current})
+	*current = ""
+}
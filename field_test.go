@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+)
+
+func fieldTestBattle(weather, terrain string, opponentTypes ...string) *battle {
+	player := testPokemon(50, 100, 100, "normal")
+	player.Name, player.Hp, player.MaxHp = "eevee", 100, 160
+	opponent := testPokemon(50, 100, 100, opponentTypes...)
+	opponent.Name, opponent.Hp, opponent.MaxHp = "foe", 160, 160
+	b := &battle{
+		player:   &battleSide{members: []*battler{{pokemon: player}}},
+		opponent: &battleSide{members: []*battler{{pokemon: opponent}}},
+		field:    fieldState{weather: weather, weatherTurns: 2, terrain: terrain, terrainTurns: 1},
+	}
+	b.player.sendOut(1)
+	b.opponent.sendOut(1)
+	return b
+}
+
+func TestEndOfTurn(t *testing.T) {
+	cases := []struct {
+		name          string
+		weather       string
+		terrain       string
+		opponentTypes []string
+		playerHp      int
+		opponentHp    int
+	}{
+		{name: "sandstorm hurts both", weather: "sandstorm", opponentTypes: []string{"water"}, playerHp: 90, opponentHp: 150},
+		{name: "sandstorm spares rock", weather: "sandstorm", opponentTypes: []string{"rock"}, playerHp: 90, opponentHp: 160},
+		{name: "hail spares ice", weather: "hail", opponentTypes: []string{"ice", "flying"}, playerHp: 90, opponentHp: 160},
+		{name: "rain deals no chip damage", weather: "rain", opponentTypes: []string{"fire"}, playerHp: 100, opponentHp: 160},
+		{name: "grassy terrain heals grounded", terrain: "grassy", opponentTypes: []string{"grass"}, playerHp: 110, opponentHp: 160},
+		{name: "grassy terrain skips flying", terrain: "grassy", opponentTypes: []string{"flying"}, playerHp: 110, opponentHp: 160},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := fieldTestBattle(c.weather, c.terrain, c.opponentTypes...)
+			b.endOfTurn()
+			if hp := b.player.current().pokemon.Hp; hp != c.playerHp {
+				t.Errorf("expected the player to have %d hp, got %d", c.playerHp, hp)
+			}
+			if hp := b.opponent.current().pokemon.Hp; hp != c.opponentHp {
+				t.Errorf("expected the opponent to have %d hp, got %d", c.opponentHp, hp)
+			}
+		})
+	}
+}
+
+func TestFieldRunsOut(t *testing.T) {
+	b := fieldTestBattle("rain", "misty", "water")
+	b.endOfTurn()
+	if b.field.weather != "rain" || b.field.weatherTurns != 1 {
+		t.Errorf("expected one turn of rain left, got %q for %d turns", b.field.weather, b.field.weatherTurns)
+	}
+	if b.field.terrain != "" {
+		t.Errorf("expected the terrain to be gone, got %q", b.field.terrain)
+	}
+	b.endOfTurn()
+	if b.field.weather != "" {
+		t.Errorf("expected the rain to stop, got %q", b.field.weather)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+//go:embed data/gyms.json
+var gymsData []byte
+
+type Gym struct {
+	Name           string  `json:"name"`
+	Region         string  `json:"region"`
+	Location       string  `json:"location"`
+	Type           string  `json:"type"`
+	Badge          string  `json:"badge"`
+	RequiredBadges int     `json:"required_badges"`
+	LevelCap       int     `json:"level_cap"`
+	Leader         Trainer `json:"leader"`
+}
+
+type RegionAccess struct {
+	Name       string   `json:"name"`
+	Badges     int      `json:"badges"`
+	Neighbours []string `json:"neighbours"`
+}
+
+type Progression struct {
+	BaseLevelCap int            `json:"base_level_cap"`
+	Regions      []RegionAccess `json:"regions"`
+	Gyms         []Gym          `json:"gyms"`
+}
+
+// Badges holds the badges the player earned, in the order they were won.
+var Badges []string
+
+func loadProgression() (Progression, error) {
+	var progression Progression
+	err := json.Unmarshal(gymsData, &progression)
+	if err != nil {
+		return Progression{}, err
+	}
+	return progression, nil
+}
+
+func hasBadge(badge string) bool {
+	for _, b := range Badges {
+		if b == badge {
+			return true
+		}
+	}
+	return false
+}
+
+// findGym looks a gym up by its own name or by the name of its leader.
+func findGym(name string) (Gym, bool, error) {
+	progression, err := loadProgression()
+	if err != nil {
+		return Gym{}, false, err
+	}
+	for _, gym := range progression.Gyms {
+		if gym.Name == name || gym.Leader.Name == name {
+			return gym, true, nil
+		}
+	}
+	return Gym{}, false, nil
+}
+
+// levelCap is the highest level party pokemon can reach with the badges earned so far.
+func levelCap() int {
+	progression, err := loadProgression()
+	if err != nil {
+		return maxLevel
+	}
+	limit := progression.BaseLevelCap
+	for _, gym := range progression.Gyms {
+		if hasBadge(gym.Badge) && gym.LevelCap > limit {
+			limit = gym.LevelCap
+		}
+	}
+	return limit
+}
+
+// regionUnlocked reports whether the player has enough badges to travel to a region.
+func regionUnlocked(region string) bool {
+	progression, err := loadProgression()
+	if err != nil {
+		return true
+	}
+	for _, access := range progression.Regions {
+		if access.Name == region {
+			return len(Badges) >= access.Badges
+		}
+	}
+	return len(Badges) >= len(progression.Gyms)
+}
+
+// areaAccessible looks up the region of a location area and reports whether the badges unlock it.
+func areaAccessible(name string) (string, bool, error) {
+	region, err := areaRegion(name)
+	if err != nil {
+		return "", false, err
+	}
+	return region, regionUnlocked(region), nil
+}
+
+// printAreas lists a page of location areas and marks those in regions the badges don't unlock yet.
+func printAreas(page LocationAreaResponse) {
+	for _, area := range page.Results {
+		region, ok, err := areaAccessible(area.Name)
+		if err == nil && !ok {
+			fmt.Printf("%s %s\n", area.Name, red("(locked, "+region+")"))
+		} else {
+			fmt.Println(area.Name)
+		}
+	}
+}
+
+// regionNeighbours lists the regions the player can travel to straight from the region.
+func regionNeighbours(region string) []string {
+	progression, err := loadProgression()
+	if err != nil {
+		return nil
+	}
+	for _, access := range progression.Regions {
+		if access.Name == region {
+			return access.Neighbours
+		}
+	}
+	return nil
+}
+
+func regionsAdjacent(from, to string) bool {
+	return slices.Contains(regionNeighbours(from), to)
+}
+
+func challengeGym(gym Gym) error {
+	if region := currentRegion(); region != gym.Region {
+		fmt.Printf("The %s is in %s, use goto to travel to an area there first\n", yellow(gym.Name), orange(gym.Region))
+		return nil
+	}
+	if len(Badges) < gym.RequiredBadges {
+		fmt.Printf("%s only accepts challengers with %d badges, you have %d\n", gym.Leader.Title, gym.RequiredBadges, len(Badges))
+		return nil
+	}
+	fmt.Printf("Welcome to the %s, the %s type gym of %s\n", yellow(gym.Name), cyan(gym.Type), orange(gym.Location))
+	won, err := challengeTrainer(gym.Leader)
+	if err != nil || !won || hasBadge(gym.Badge) {
+		return err
+	}
+	Badges = append(Badges, gym.Badge)
+	fmt.Printf("You received the %s!\n", boldYellow(gym.Badge))
+	fmt.Printf("Your pokemon can now grow up to level %s\n", boldGreen(levelCap()))
+	return nil
+}
+
+func commandBadges(_ *Config, _ string) error {
+	progression, err := loadProgression()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %d/%d\n", orange("Badges:"), len(Badges), len(progression.Gyms))
+	for _, gym := range progression.Gyms {
+		if hasBadge(gym.Badge) {
+			fmt.Printf("%s %s from %s\n", boldGreen("[x]"), yellow(gym.Badge), gym.Leader.Title)
+		} else {
+			fmt.Printf("[ ] %s: challenge %s in %s (%d badges needed)\n", gym.Badge, blue(gym.Leader.Name), gym.Location, gym.RequiredBadges)
+		}
+	}
+	fmt.Printf("%s %d\n", boldYellow("level cap:"), levelCap())
+	fmt.Println(boldYellow("regions:"))
+	for _, access := range progression.Regions {
+		if regionUnlocked(access.Name) {
+			fmt.Printf("- %s\n", green(access.Name))
+		} else {
+			fmt.Printf("- %s (%d badges needed)\n", red(access.Name), access.Badges)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGymsData(t *testing.T) {
+	progression, err := loadProgression()
+	if err != nil {
+		t.Fatalf("unable to load gyms: %v", err)
+	}
+	previousCap := progression.BaseLevelCap
+	for i, gym := range progression.Gyms {
+		checkTeam(t, gym.Leader)
+		if gym.RequiredBadges != i {
+			t.Errorf("%s requires %d badges, expected gyms in badge order", gym.Name, gym.RequiredBadges)
+		}
+		if gym.LevelCap <= previousCap {
+			t.Errorf("%s raises the level cap to %d, which is not above %d", gym.Name, gym.LevelCap, previousCap)
+		}
+		previousCap = gym.LevelCap
+	}
+}
+
+func TestLockedAreaIsNotSearchable(t *testing.T) {
+	useTestCache(t)
+	cache.Add("https://pokeapi.co/api/v2/location-area/south-province-area-one/", []byte(`{"name": "south-province-area-one",
+		"location": {"name": "south-province", "url": "https://pokeapi.co/api/v2/location/south-province/"},
+		"pokemon_encounters": [{"pokemon": {"name": "lechonk", "url": "https://pokeapi.co/api/v2/pokemon/915/"}, "version_details": [
+			{"version": {"name": "scarlet"}, "encounter_details": [{"min_level": 2, "max_level": 4, "chance": 100, "method": {"name": "walk"}}]}
+		]}]}`))
+	cache.Add("https://pokeapi.co/api/v2/location/south-province/", []byte(`{"name": "south-province", "region": {"name": "paldea"}}`))
+	CurrentArea, Badges, DexSeen = "south-province-area-one", nil, nil
+	catchablePokemon = map[string]PokemonInformation{}
+	defer func() { CurrentArea, catchablePokemon = "", nil }()
+
+	if _, ok, err := areaAccessible(CurrentArea); err != nil || ok {
+		t.Errorf("expected paldea to be locked without badges, got accessible %v (%v)", ok, err)
+	}
+	if err := commandExplore(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := commandFind(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(DexSeen) != 0 || len(catchablePokemon) != 0 {
+		t.Errorf("expected nothing to be seen or found in a locked region, got %v and %v", DexSeen, catchablePokemon)
+	}
+}
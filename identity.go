@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NextID is the ID the next caught pokemon gets, IDs are never reused.
+var NextID = 1
+
+func newID() string {
+	id := strconv.Itoa(NextID)
+	NextID++
+	return id
+}
+
+// displayName is the nickname of a caught pokemon, or its species name when it has none.
+func displayName(pokemon PokemonInformation) string {
+	if pokemon.Nickname != "" {
+		return pokemon.Nickname
+	}
+	return pokemon.Name
+}
+
+// label tells caught pokemon of the same species apart by their ID.
+func label(pokemon PokemonInformation) string {
+	if pokemon.Nickname != "" {
+		return fmt.Sprintf("#%s %s (%s)", pokemon.ID, yellow(pokemon.Nickname), pokemon.Name)
+	}
+	return fmt.Sprintf("#%s %s", pokemon.ID, yellow(pokemon.Name))
+}
+
+// sortedKeys returns the Pokedex keys in the order the pokemon were caught.
+func sortedKeys() []string {
+	keys := make([]string, 0, len(PokeDex))
+	for key := range PokeDex {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+	return keys
+}
+
+// idOrNickname returns the Pokedex key of the pokemon with the ID or nickname.
+func idOrNickname(ref string) (string, bool) {
+	id := strings.TrimPrefix(ref, "#")
+	if _, ok := PokeDex[id]; ok {
+		return id, true
+	}
+	for key, pokemon := range PokeDex {
+		if pokemon.Nickname != "" && strings.EqualFold(pokemon.Nickname, ref) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// findPokemon resolves an ID, a nickname or a species name to a Pokedex key and explains why when it can't.
+// A species the player caught more than once has to be picked by ID or nickname.
+func findPokemon(ref string) (string, bool) {
+	if key, ok := idOrNickname(ref); ok {
+		return key, true
+	}
+	matches := []string{}
+	for _, key := range sortedKeys() {
+		if PokeDex[key].Name == ref {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		fmt.Printf("You have not yet caught %s\n", yellow(ref))
+		return "", false
+	case 1:
+		return matches[0], true
+	}
+	fmt.Printf("You have %d %s, pick one by ID or nickname:\n", len(matches), yellow(ref))
+	for _, key := range matches {
+		pokemon := PokeDex[key]
+		fmt.Printf("- %s level %d\n", label(pokemon), pokemon.Level)
+	}
+	return "", false
+}
+
+// maxNicknameLength is the longest nickname the games allow.
+const maxNicknameLength = 12
+
+func commandNickname(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return fmt.Errorf("usage: nickname <pokemon> <name>")
+	}
+	key, ok := findPokemon(fields[0])
+	if !ok {
+		return nil
+	}
+	nickname := fields[1]
+	if len(nickname) > maxNicknameLength {
+		fmt.Printf("A nickname can be at most %d characters long\n", maxNicknameLength)
+		return nil
+	}
+	if _, err := strconv.Atoi(strings.TrimPrefix(nickname, "#")); err == nil {
+		fmt.Println("A nickname can't be a number, numbers are pokemon IDs")
+		return nil
+	}
+	if other, taken := idOrNickname(nickname); taken && other != key {
+		fmt.Printf("%s is already called %s\n", label(PokeDex[other]), nickname)
+		return nil
+	}
+	pokemon := PokeDex[key]
+	fmt.Printf("%s is now called %s\n", label(pokemon), boldGreen(nickname))
+	pokemon.Nickname = nickname
+	PokeDex[key] = pokemon
+	return nil
+}
+
+// migratePokedex gives pokemon from saves keyed by species name an ID and points the party at the new keys.
+func migratePokedex() {
+	for _, pokemon := range PokeDex {
+		if n, err := strconv.Atoi(pokemon.ID); err == nil && n >= NextID {
+			NextID = n + 1
+		}
+	}
+	renamed := make(map[string]string)
+	names := []string{}
+	for key, pokemon := range PokeDex {
+		if pokemon.ID == "" {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pokemon := PokeDex[name]
+		delete(PokeDex, name)
+		pokemon.ID = newID()
+		PokeDex[pokemon.ID] = pokemon
+		renamed[name] = pokemon.ID
+	}
+	for i, member := range Party {
+		if id, ok := renamed[member]; ok {
+			Party[i] = id
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMigratePokedex(t *testing.T) {
+	PokeDex = map[string]PokemonInformation{
+		"pidgey":  {Name: "pidgey", Level: 7},
+		"rattata": {Name: "rattata", Level: 3},
+	}
+	Party = []string{"rattata"}
+	NextID = 1
+	migratePokedex()
+
+	if len(PokeDex) != 2 {
+		t.Fatalf("expected 2 pokemon after the migration, got %d", len(PokeDex))
+	}
+	if PokeDex["1"].Name != "pidgey" || PokeDex["1"].ID != "1" || PokeDex["1"].Level != 7 {
+		t.Errorf("expected pidgey to become #1, got %+v", PokeDex["1"])
+	}
+	if PokeDex["2"].Name != "rattata" {
+		t.Errorf("expected rattata to become #2, got %s", PokeDex["2"].Name)
+	}
+	if len(Party) != 1 || Party[0] != "2" {
+		t.Errorf("expected the party to point at #2, got %v", Party)
+	}
+	if NextID != 3 {
+		t.Errorf("expected the next ID to be 3, got %d", NextID)
+	}
+	migratePokedex()
+	if len(PokeDex) != 2 || NextID != 3 {
+		t.Error("expected migrating twice to change nothing")
+	}
+}
+
+func TestFindPokemon(t *testing.T) {
+	PokeDex = map[string]PokemonInformation{
+		"1": {ID: "1", Name: "pidgey"},
+		"2": {ID: "2", Name: "pidgey", Nickname: "Birdy"},
+		"3": {ID: "3", Name: "rattata"},
+	}
+	cases := []struct {
+		ref      string
+		expected string
+		ok       bool
+	}{
+		{ref: "1", expected: "1", ok: true},
+		{ref: "#2", expected: "2", ok: true},
+		{ref: "birdy", expected: "2", ok: true},
+		{ref: "rattata", expected: "3", ok: true},
+		{ref: "pidgey", ok: false},
+		{ref: "mew", ok: false},
+	}
+	for _, c := range cases {
+		t.Run(c.ref, func(t *testing.T) {
+			key, ok := findPokemon(c.ref)
+			if ok != c.ok || key != c.expected {
+				t.Errorf("expected %q %v, got %q %v", c.expected, c.ok, key, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fullHeal is the heal value of items that restore all hp.
+const fullHeal = -1
+
+// Item is the part of the PokeAPI /item response the bag shows.
+type Item struct {
+	Name          string           `json:"name"`
+	Cost          int              `json:"cost"`
+	Category      NamedAPIResource `json:"category"`
+	EffectEntries []struct {
+		ShortEffect string           `json:"short_effect"`
+		Language    NamedAPIResource `json:"language"`
+	} `json:"effect_entries"`
+}
+
+// itemEffect is what an item does, PokeAPI only describes effects in text so they are listed here.
+type itemEffect struct {
+	// heal is the hp restored, fullHeal restores all of it
+	heal int
+	// healDivisor restores a part of the max hp instead, 4 restores a quarter
+	healDivisor int
+	cures       []string
+	stat        string
+	stages      int
+	// berries are eaten by their holder once its hp falls to half or it gets a status they cure
+	berry bool
+	// evolution items are used outside of battle
+	evolution bool
+}
+
+var allStatuses = []string{"paralysis", "sleep", "freeze", "burn", "poison", "confusion"}
+
+var itemEffects = map[string]itemEffect{
+	"potion":        {heal: 20},
+	"super-potion":  {heal: 60},
+	"hyper-potion":  {heal: 120},
+	"max-potion":    {heal: fullHeal},
+	"full-restore":  {heal: fullHeal, cures: allStatuses},
+	"antidote":      {cures: []string{"poison"}},
+	"paralyze-heal": {cures: []string{"paralysis"}},
+	"awakening":     {cures: []string{"sleep"}},
+	"burn-heal":     {cures: []string{"burn"}},
+	"ice-heal":      {cures: []string{"freeze"}},
+	"full-heal":     {cures: allStatuses},
+	"x-attack":      {stat: "attack", stages: 2},
+	"x-defense":     {stat: "defense", stages: 2},
+	"x-sp-atk":      {stat: "special-attack", stages: 2},
+	"x-sp-def":      {stat: "special-defense", stages: 2},
+	"x-speed":       {stat: "speed", stages: 2},
+	"oran-berry":    {heal: 10, berry: true},
+	"sitrus-berry":  {healDivisor: 4, berry: true},
+	"lum-berry":     {cures: allStatuses, berry: true},
+	"cheri-berry":   {cures: []string{"paralysis"}, berry: true},
+	"chesto-berry":  {cures: []string{"sleep"}, berry: true},
+	"pecha-berry":   {cures: []string{"poison"}, berry: true},
+	"rawst-berry":   {cures: []string{"burn"}, berry: true},
+	"aspear-berry":  {cures: []string{"freeze"}, berry: true},
+	"fire-stone":    {evolution: true},
+	"water-stone":   {evolution: true},
+	"thunder-stone": {evolution: true},
+	"leaf-stone":    {evolution: true},
+	"moon-stone":    {evolution: true},
+	"sun-stone":     {evolution: true},
+	"shiny-stone":   {evolution: true},
+	"dusk-stone":    {evolution: true},
+	"dawn-stone":    {evolution: true},
+	"ice-stone":     {evolution: true},
+}
+
+// Bag holds the items the player carries and how many of each, a new game starts with a few.
+var Bag = map[string]int{
+	"poke-ball":     10,
+	"potion":        5,
+	"antidote":      2,
+	"paralyze-heal": 2,
+	"x-attack":      1,
+	"sitrus-berry":  1,
+}
+
+func getItem(name string) (Item, error) {
+	data, err := GetData(cache, "https://pokeapi.co/api/v2/item/"+name)
+	if err != nil {
+		return Item{}, err
+	}
+	var item Item
+	err = json.Unmarshal(data, &item)
+	if err != nil {
+		return Item{}, err
+	}
+	return item, nil
+}
+
+func (i Item) shortEffect() string {
+	for _, entry := range i.EffectEntries {
+		if entry.Language.Name == "en" {
+			return entry.ShortEffect
+		}
+	}
+	return ""
+}
+
+func addItem(name string, count int) {
+	if Bag == nil {
+		Bag = make(map[string]int)
+	}
+	Bag[name] += count
+}
+
+// takeItem removes one item from the bag and reports whether there was one.
+func takeItem(name string) bool {
+	if Bag[name] <= 0 {
+		return false
+	}
+	Bag[name]--
+	if Bag[name] == 0 {
+		delete(Bag, name)
+	}
+	return true
+}
+
+func cures(effect itemEffect, status string) bool {
+	for _, cured := range effect.cures {
+		if cured == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (e itemEffect) healAmount(pokemon PokemonInformation) int {
+	amount := e.heal
+	if e.healDivisor > 0 {
+		amount = pokemon.MaxHp / e.healDivisor
+	}
+	if amount == fullHeal || amount > pokemon.MaxHp-pokemon.Hp {
+		amount = pokemon.MaxHp - pokemon.Hp
+	}
+	return amount
+}
+
+// usefulOn reports whether using the item would change anything for the pokemon.
+func (e itemEffect) usefulOn(b *battler) bool {
+	if b.fainted() {
+		return false
+	}
+	return e.healAmount(b.pokemon) > 0 || cures(e, b.status) || (e.stat != "" && b.stages[e.stat] < maxStage)
+}
+
+// applyItem heals, cures or boosts the pokemon and reports how much hp it restored.
+func applyItem(b *battler, effect itemEffect) int {
+	heal := effect.healAmount(b.pokemon)
+	if heal > 0 {
+		b.pokemon.Hp += heal
+		fmt.Printf("%s restored %s hp\n", yellow(b.pokemon.Name), green(heal))
+	}
+	if cures(effect, b.status) {
+		fmt.Printf("%s was cured of its %s\n", yellow(b.pokemon.Name), b.status)
+		b.status = ""
+	}
+	if effect.stat != "" {
+		changeStage(b, effect.stat, effect.stages)
+	}
+	return heal
+}
+
+// useItem spends the turn using an item from the bag on a pokemon of the player's side.
+func (b *battle) useItem(action battleAction) {
+	effect := itemEffects[action.item]
+	if !effect.usefulOn(action.target) || !takeItem(action.item) {
+		fmt.Printf("The %s had no effect\n", action.item)
+		return
+	}
+	fmt.Printf("You used a %s on %s\n", cyan(action.item), yellow(action.target.pokemon.Name))
+	heal := applyItem(action.target, effect)
+	b.record(battleEvent{Kind: "item", Side: b.sideName(action.side), Pokemon: action.target.pokemon.Name, Item: action.item, Heal: heal, Hp: action.target.pokemon.Hp})
+}
+
+// checkHeldItem lets the pokemon eat its berry when its hp is at half or it has a status the berry cures.
+func (b *battle) checkHeldItem(self *battler) {
+	effect, ok := itemEffects[self.pokemon.HeldItem]
+	if !ok || !effect.berry || self.fainted() {
+		return
+	}
+	lowHp := (effect.heal != 0 || effect.healDivisor > 0) && self.pokemon.Hp*2 <= self.pokemon.MaxHp
+	if !lowHp && !cures(effect, self.status) {
+		return
+	}
+	item := self.pokemon.HeldItem
+	self.pokemon.HeldItem = ""
+	fmt.Printf("%s ate its %s!\n", yellow(self.pokemon.Name), cyan(item))
+	heal := applyItem(self, effect)
+	b.record(battleEvent{Kind: "item", Side: b.sideName(b.sideOf(self)), Pokemon: self.pokemon.Name, Item: item, Heal: heal, Hp: self.pokemon.Hp})
+}
+
+// battleItem parses "use <item> [pokemon]" for the pokemon in a slot and returns the action, ok is false when it can't be used.
+func battleItem(side *battleSide, active *battler, args string) (battleAction, bool) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
+		fmt.Println("usage: use <item> [pokemon]")
+		return battleAction{}, false
+	}
+	name := fields[0]
+	effect, ok := itemEffects[name]
+	if Bag[name] <= 0 {
+		fmt.Printf("You have no %s in your bag\n", name)
+		return battleAction{}, false
+	}
+	if _, isBall := balls[name]; isBall {
+		fmt.Printf("Throw balls with %s\n", blue("catch <ball>"))
+		return battleAction{}, false
+	}
+	if !ok || effect.evolution {
+		fmt.Printf("The %s can't be used in battle\n", name)
+		return battleAction{}, false
+	}
+	target := active
+	if len(fields) == 2 {
+		i := memberIndex(side, fields[1])
+		if i < 0 {
+			fmt.Printf("%s is not in your party\n", yellow(fields[1]))
+			return battleAction{}, false
+		}
+		target = side.members[i]
+	}
+	if !effect.usefulOn(target) {
+		fmt.Printf("The %s would have no effect on %s\n", name, yellow(target.pokemon.Name))
+		return battleAction{}, false
+	}
+	return battleAction{side: side, actor: active, item: name, target: target, switchTo: -1}, true
+}
+
+func printBag() {
+	if len(Bag) == 0 {
+		fmt.Println("Your bag is empty")
+		return
+	}
+	names := make([]string, 0, len(Bag))
+	for name := range Bag {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println(orange("Your bag:"))
+	for _, name := range names {
+		description := ""
+		if item, err := getItem(name); err == nil && item.shortEffect() != "" {
+			description = ": " + item.shortEffect()
+		}
+		fmt.Printf("- %s x%d%s\n", cyan(name), Bag[name], description)
+	}
+}
+
+func commandBag(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		printBag()
+		return nil
+	}
+	switch fields[0] {
+	case "give":
+		if len(fields) != 3 {
+			return fmt.Errorf("usage: bag give <pokemon> <item>")
+		}
+		return bagGive(fields[1], fields[2])
+	case "take":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: bag take <pokemon>")
+		}
+		return bagTake(fields[1])
+	case "use":
+		if len(fields) != 3 {
+			return fmt.Errorf("usage: bag use <item> <pokemon>")
+		}
+		return bagUse(fields[1], fields[2])
+	}
+	return fmt.Errorf("unknown bag command %s, use give, take or use", fields[0])
+}
+
+// holdable reports whether a pokemon does something with the item when it holds it, only berries do so far.
+func holdable(itemName string) bool {
+	return itemEffects[itemName].berry
+}
+
+func bagGive(ref, itemName string) error {
+	if !holdable(itemName) {
+		return fmt.Errorf("%s does nothing when held, only berries can be given", itemName)
+	}
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	pokemon := PokeDex[key]
+	pokemonName := displayName(pokemon)
+	if Bag[itemName] <= 0 {
+		fmt.Printf("You have no %s in your bag\n", itemName)
+		return nil
+	}
+	if pokemon.HeldItem != "" {
+		addItem(pokemon.HeldItem, 1)
+		fmt.Printf("You took the %s from %s\n", cyan(pokemon.HeldItem), yellow(pokemonName))
+	}
+	takeItem(itemName)
+	pokemon.HeldItem = itemName
+	PokeDex[key] = pokemon
+	fmt.Printf("%s is now holding a %s\n", yellow(pokemonName), cyan(itemName))
+	return nil
+}
+
+func bagTake(ref string) error {
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	pokemon := PokeDex[key]
+	pokemonName := displayName(pokemon)
+	if pokemon.HeldItem == "" {
+		fmt.Printf("%s is not holding anything\n", yellow(pokemonName))
+		return nil
+	}
+	addItem(pokemon.HeldItem, 1)
+	fmt.Printf("You took the %s from %s\n", cyan(pokemon.HeldItem), yellow(pokemonName))
+	pokemon.HeldItem = ""
+	PokeDex[key] = pokemon
+	return nil
+}
+
+// bagUse uses an evolution item outside of battle, the item is only used up when the pokemon evolves.
+func bagUse(itemName, ref string) error {
+	key, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
+	if Bag[itemName] <= 0 {
+		fmt.Printf("You have no %s in your bag\n", itemName)
+		return nil
+	}
+	if !itemEffects[itemName].evolution {
+		fmt.Printf("The %s can only be used in battle\n", itemName)
+		return nil
+	}
+	evolved, err := offerEvolution(key, evolutionTrigger{kind: "use-item", item: itemName})
+	if err != nil {
+		return err
+	}
+	if !evolved {
+		fmt.Printf("You kept the %s\n", itemName)
+		return nil
+	}
+	takeItem(itemName)
+	return nil
+}
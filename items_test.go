@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestHeldBerries(t *testing.T) {
+	cases := []struct {
+		name     string
+		item     string
+		hp       int
+		status   string
+		expected int
+		eaten    bool
+	}{
+		{name: "sitrus berry at half hp", item: "sitrus-berry", hp: 50, expected: 75, eaten: true},
+		{name: "sitrus berry above half hp", item: "sitrus-berry", hp: 51, expected: 51},
+		{name: "oran berry", item: "oran-berry", hp: 20, expected: 30, eaten: true},
+		{name: "lum berry cures", item: "lum-berry", hp: 100, status: "paralysis", expected: 100, eaten: true},
+		{name: "cheri berry ignores poison", item: "cheri-berry", hp: 100, status: "poison", expected: 100},
+		{name: "potions are not eaten", item: "potion", hp: 10, expected: 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			holder := &battler{pokemon: PokemonInformation{Name: "snorlax", Hp: c.hp, MaxHp: 100, HeldItem: c.item}, status: c.status}
+			b := &battle{player: &battleSide{members: []*battler{holder}}, opponent: &battleSide{}}
+			b.checkHeldItem(holder)
+			if holder.pokemon.Hp != c.expected {
+				t.Errorf("expected %d hp, got %d", c.expected, holder.pokemon.Hp)
+			}
+			if eaten := holder.pokemon.HeldItem == ""; eaten != c.eaten {
+				t.Errorf("expected eaten to be %v, got %v", c.eaten, eaten)
+			}
+			if c.eaten && holder.status != "" {
+				t.Errorf("expected the status to be cured, got %s", holder.status)
+			}
+		})
+	}
+}
+
+func TestBattleItemUsesBag(t *testing.T) {
+	Bag = map[string]int{"potion": 1, "fire-stone": 1}
+	active := &battler{pokemon: PokemonInformation{Name: "eevee", Hp: 90, MaxHp: 100}}
+	side := &battleSide{members: []*battler{active}}
+	side.sendOut(1)
+	b := &battle{player: side, opponent: &battleSide{}}
+
+	if _, ok := battleItem(side, active, "fire-stone"); ok {
+		t.Error("expected evolution items to be refused in battle")
+	}
+	if _, ok := battleItem(side, active, "super-potion"); ok {
+		t.Error("expected items that are not in the bag to be refused")
+	}
+	action, ok := battleItem(side, active, "potion")
+	if !ok {
+		t.Fatal("expected the potion to be usable")
+	}
+	b.execute(action)
+	if active.pokemon.Hp != 100 {
+		t.Errorf("expected the potion to heal up to max hp, got %d", active.pokemon.Hp)
+	}
+	if Bag["potion"] != 0 {
+		t.Errorf("expected the potion to be used up, %d left", Bag["potion"])
+	}
+	if _, ok := battleItem(side, active, "potion"); ok {
+		t.Error("expected an empty bag to refuse the potion")
+	}
+}
+
+func TestBattleItemTargetByNickname(t *testing.T) {
+	Bag = map[string]int{"potion": 1}
+	active := &battler{key: "1", pokemon: PokemonInformation{Name: "eevee", Hp: 100, MaxHp: 100}}
+	benched := &battler{key: "2", pokemon: PokemonInformation{Name: "pikachu", Nickname: "Sparky", Hp: 10, MaxHp: 100}}
+	side := &battleSide{members: []*battler{active, benched}}
+	side.sendOut(1)
+	for _, ref := range []string{"sparky", "#2", "pikachu"} {
+		action, ok := battleItem(side, active, "potion "+ref)
+		if !ok || action.target != benched {
+			t.Errorf("%s: expected the potion to target the benched pikachu", ref)
+		}
+	}
+	if _, ok := battleItem(side, active, "potion raichu"); ok {
+		t.Error("expected a pokemon outside the party to be refused")
+	}
+}
+
+func TestBagGiveOnlyHoldableItems(t *testing.T) {
+	PokeDex = map[string]PokemonInformation{"1": {ID: "1", Name: "snorlax"}}
+	Bag = map[string]int{"poke-ball": 1, "potion": 1, "sitrus-berry": 1}
+	defer func() { PokeDex, Bag = nil, nil }()
+	cases := []struct {
+		item          string
+		expectedError bool
+	}{
+		{item: "poke-ball", expectedError: true},
+		{item: "potion", expectedError: true},
+		{item: "sitrus-berry", expectedError: false},
+	}
+	for _, c := range cases {
+		err := bagGive("#1", c.item)
+		if (err != nil) != c.expectedError {
+			t.Errorf("%s: expected error %v, got %v", c.item, c.expectedError, err)
+		}
+	}
+	if PokeDex["1"].HeldItem != "sitrus-berry" || Bag["poke-ball"] != 1 || Bag["potion"] != 1 {
+		t.Errorf("expected only the berry to be given, holding %q with bag %v", PokeDex["1"].HeldItem, Bag)
+	}
+}
@@ -15,7 +15,6 @@ import (
 	"math/rand"
 	"strconv"
 
-	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -47,31 +46,54 @@ type LocationAreaResponse struct {
 }
 
 type LocationArea struct {
+	Name              string           `json:"name"`
+	Location          NamedAPIResource `json:"location"`
 	PokemonEncounters []struct {
 		Pokemon struct {
 			Name string `json:"name"`
+			URL  string `json:"url"`
 		} `json:"pokemon"`
+		VersionDetails []VersionEncounterDetail `json:"version_details"`
 	} `json:"pokemon_encounters"`
 }
 
 type PokemonInformation struct {
-	MaxHp                  int
-	Hp                     int
-	Speed                  int
-	Attack                 int
-	MaxAttack              int
-	Defense                int
-	SpecialDefense         int
-	SpecialAttack          int
-	Level                  int
+	MaxHp          int
+	Hp             int
+	Speed          int
+	Attack         int
+	MaxAttack      int
+	Defense        int
+	SpecialDefense int
+	SpecialAttack  int
+	Level          int
+	Experience     int
+	GrowthRate     string
+	IVs            map[string]int
+	EVs            map[string]int
+	Nature         Nature
+	Ability        string
+	HeldItem       string
+	Gender         string // male, female or genderless
+	Shiny          bool
+	// ID is the Pokedex key of a caught pokemon, Nickname is optional
+	ID                     string
+	Nickname               string
 	BaseExperience         int                   `json:"base_experience"`
 	Name                   string                `json:"name"`
 	Height                 int                   `json:"height"`
 	Weight                 int                   `json:"weight"`
 	PokemonMovesAPIEntries []PokemonMoveAPIEntry `json:"moves"`
 	Moves                  map[string]Move
-	Stats                  []Stat  `json:"stats"`
-	Types                  []PType `json:"types"`
+	Stats                  []Stat           `json:"stats"`
+	Types                  []PType          `json:"types"`
+	Abilities              []PokemonAbility `json:"abilities"`
+	Species                NamedAPIResource `json:"species"`
+}
+
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
 }
 
 type PokemonMoveAPIEntry struct {
@@ -85,6 +107,9 @@ type Move struct {
 	Name     string `json:"name"`
 	Power    int    `json:"power"`
 	Accuracy int    `json:"accuracy"`
+	Priority int    `json:"priority"`
+
+	Target NamedAPIResource `json:"target"`
 
 	Type struct {
 		Name string `json:"name"`
@@ -98,10 +123,15 @@ type Move struct {
 		Effect      string `json:"effect"`
 		ShortEffect string `json:"short_effect"`
 	} `json:"effect_entries"`
+
+	Meta struct {
+		CritRate int `json:"crit_rate"`
+	} `json:"meta"`
 }
 
 type Stat struct {
 	BaseStat int      `json:"base_stat"`
+	Effort   int      `json:"effort"`
 	StatInfo StatInfo `json:"stat"`
 }
 
@@ -150,7 +180,7 @@ func simpelLearnMove(pokemon *PokemonInformation) ([]string, error) {
 		if err != nil {
 			return []string{}, err
 		}
-		if move.DamageClass.Name == "status" {
+		if move.DamageClass.Name == "status" || !supportedMove(move) {
 			continue
 		} else {
 			pokemon.Moves[move.Name] = move
@@ -160,18 +190,22 @@ func simpelLearnMove(pokemon *PokemonInformation) ([]string, error) {
 	return learnt_moves, nil
 }
 
-func commandLearnMove(_ *Config, pokemonName string) error {
+func commandLearnMove(_ *Config, ref string) error {
+	pokemonName, ok := findPokemon(ref)
+	if !ok {
+		return nil
+	}
 	for i, move := range PokeDex[pokemonName].PokemonMovesAPIEntries {
 		fmt.Printf("%v: %v, ", boldYellow(i), cyan(move.MoveInfo.Name))
 	}
-	fmt.Printf("What move do you want to learn for %s\nplease type the number befor the move:", boldGreen(pokemonName))
+	fmt.Printf("What move do you want to learn for %s\nplease type the number befor the move:", boldGreen(displayName(PokeDex[pokemonName])))
 	scanner := bufio.NewScanner(os.Stdin)
 	for {
 		if scanner.Scan() {
 			input := scanner.Text()
 			moveIndex, err := strconv.Atoi(input)
 			if err != nil {
-				fmt.Printf("\nWhat move do you want to learn for %s\nplease type the number befor the move:", boldGreen(pokemonName))
+				fmt.Printf("\nWhat move do you want to learn for %s\nplease type the number befor the move:", boldGreen(displayName(PokeDex[pokemonName])))
 				continue
 			}
 			moveData, err := GetData(cache, PokeDex[pokemonName].PokemonMovesAPIEntries[moveIndex].MoveInfo.URL)
@@ -183,137 +217,38 @@ func commandLearnMove(_ *Config, pokemonName string) error {
 			if err != nil {
 				return err
 			}
-			if move.DamageClass.Name == "status" {
+			if !supportedMove(move) {
 				fmt.Println("Sorry this move is not yet supported please choose another one")
 				continue
 			}
 			PokeDex[pokemonName].Moves[move.Name] = move
-			fmt.Printf("%s learnt move %s\n", boldGreen(pokemonName), cyan(move.Name))
+			fmt.Printf("%s learnt move %s\n", boldGreen(displayName(PokeDex[pokemonName])), cyan(move.Name))
 		}
 		return nil
 	}
 }
 
-func playerMove(pokemon PokemonInformation, opponentPokemon *PokemonInformation) {
-	for move := range pokemon.Moves {
-		fmt.Printf("%s type: %s\n", cyan(pokemon.Moves[move].Name), green(pokemon.Moves[move].Type.Name))
-	}
-	fmt.Printf("choose a move to play:")
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		if scanner.Scan() {
-			input := scanner.Text()
-			move, ok := pokemon.Moves[input]
-			if !ok {
-				for move := range pokemon.Moves {
-					fmt.Printf("%s type: %s\n", cyan(pokemon.Moves[move].Name), green(pokemon.Moves[move].Type.Name))
-				}
-				fmt.Printf("\nchoose a move to play:")
-				continue
-			}
-			fmt.Printf("%s plays %s\n", yellow(pokemon.Name), cyan(move.Name))
-			calculateDamageMove(pokemon, opponentPokemon, move)
-			break
-		}
+func commandFind(_ *Config, args string) error {
+	area, method, err := parseFindArgs(args)
+	if err != nil {
+		return err
 	}
-}
-
-func commandBattle(_ *Config, pokemonName string) error {
-	if len(PokeDex) < 1 {
-		fmt.Printf("You have no pokemon to fight with\nGo catch some pokemon!")
-		return nil
+	area, err = areaOrCurrent(area)
+	if err != nil {
+		return err
 	}
-	pokemon, ok := catchablePokemon[pokemonName]
-	if !ok {
-		fmt.Println("You can't fight a pokemon you have not yet found using the find command")
+	if area != CurrentArea {
+		fmt.Printf("You are not at %s, use goto %s first\n", orange(area), area)
 		return nil
 	}
-	firstMove := true
-	var your_pokemon PokemonInformation
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		fmt.Printf("Choose a pokemon to fight with:")
-		if scanner.Scan() {
-			input := scanner.Text()
-			pokemon1, ok := PokeDex[input]
-			if ok {
-				your_pokemon = pokemon1
-				break
-			} else {
-				fmt.Printf("This is not a pokemon you can fight with\n")
-				commandPokedex(&Config{}, "")
-			}
-		}
-	}
-	fmt.Printf("\n")
-	resetStats(&pokemon)
-	resetStats(&your_pokemon)
-	learnt_moves, err := simpelLearnMove(&pokemon)
+	region, ok, err := areaAccessible(area)
 	if err != nil {
 		return err
 	}
-	for pokemon.Hp > 0 && your_pokemon.Hp > 0 {
-		if pokemon.Speed > your_pokemon.Speed {
-			firstMove = false
-			index := rand.Intn(len(pokemon.Moves))
-			move := learnt_moves[index]
-			fmt.Printf("%s plays %s\n", yellow(pokemon.Name), cyan(pokemon.Moves[move].Name))
-			calculateDamageMove(pokemon, &your_pokemon, pokemon.Moves[move])
-			if your_pokemon.Hp <= 0 {
-				break
-			}
-		} else {
-			playerMove(your_pokemon, &pokemon)
-			if pokemon.Hp <= 0 {
-				break
-			}
-		}
-		if !firstMove {
-			playerMove(your_pokemon, &pokemon)
-			if pokemon.Hp <= 0 {
-				break
-			}
-		} else {
-			index := rand.Intn(len(pokemon.Moves))
-			move := learnt_moves[index]
-			fmt.Printf("%s plays %s\n", yellow(pokemon.Name), cyan(pokemon.Moves[move].Name))
-			calculateDamageMove(pokemon, &your_pokemon, pokemon.Moves[move])
-			if your_pokemon.Hp <= 0 {
-				break
-			}
-		}
-	}
-	if pokemon.Hp <= 0 {
-		fmt.Printf("%s %s\n", yellow(pokemon.Name), boldRed("fainted"))
-		fmt.Printf("maxHp: %v\n", your_pokemon.MaxHp)
-		your_pokemon.Level += 1
-		your_pokemon.MaxHp = int(math.Round((float64(your_pokemon.MaxHp) * 1.02)))
-		fmt.Printf("newMaxHp: %v\n", your_pokemon.MaxHp)
-		fmt.Println(boldGreen("You won!"))
-	} else {
-		fmt.Printf("%s %s\n", yellow(your_pokemon.Name), boldRed("fainted"))
-	}
-	resetStats(&pokemon)
-	resetStats(&your_pokemon)
-	return nil
-}
-
-func calculateDamageMove(attackerPokemon PokemonInformation, pokemon *PokemonInformation, move Move) {
-	if rand.Intn(101) > move.Accuracy {
-		fmt.Printf("%s %s %s!\n", pokemon.Name, yellow("dodged"), move.Name)
-		return
-	}
-	var damage int
-	if move.DamageClass.Name == "physical" {
-		damage = 5 * (((2*attackerPokemon.Level/5+2)*move.Power*attackerPokemon.Attack/pokemon.Defense)/50 + 2)
-	} else {
-		damage = 5 * (((2*attackerPokemon.Level/5+2)*move.Power*attackerPokemon.SpecialAttack/pokemon.SpecialDefense)/50 + 2)
+	if !ok {
+		fmt.Printf("%s lies in %s, you need more badges to go there\n", orange(area), red(region))
+		return nil
 	}
-	fmt.Printf("%s dealt: %s\n", boldRed("Damage"), red(fmt.Sprintf("%d", damage)))
-	pokemon.Hp -= damage
-}
-
-func commandFind(_ *Config, area string) error {
 	fmt.Printf("Looking for pokemon at %s\n", orange(area))
 	url := "https://pokeapi.co/api/v2/location-area/" + area + "/"
 	data, err := GetData(cache, url)
@@ -325,46 +260,77 @@ func commandFind(_ *Config, area string) error {
 	if err != nil {
 		return err
 	}
-	index := rand.Intn(len(areaInfo.PokemonEncounters))
-	pokemonName := areaInfo.PokemonEncounters[index].Pokemon.Name
-	url = "https://pokeapi.co/api/v2/pokemon/" + pokemonName
-	data1, err := GetData(cache, url)
-	if err != nil {
-		return err
+	table := encounterTable(areaInfo, method)
+	if len(table) == 0 {
+		methods := encounterMethods(areaInfo)
+		if len(methods) == 0 {
+			fmt.Println("There are no pokemon here")
+		} else {
+			fmt.Printf("You can't find pokemon by %s here, try %s\n", method, strings.Join(methods, ", "))
+		}
+		return nil
 	}
-
-	var pokemon PokemonInformation
-	err = json.Unmarshal(data1, &pokemon)
+	slot := pickEncounter(table, rand.Intn)
+	pokemonName := slot.name
+	pokemon, err := encounter(pokemonName, rand.Intn)
 	if err != nil {
 		return err
 	}
+	pokemon.Level = rollLevel(slot, rand.Intn)
 	pokemon.Moves = make(map[string]Move)
-	fmt.Printf("You found a %s!\nYou are now able to catch %s using the %s command\nor you can %s it using the %s command\n", yellow(pokemonName), blue("catch"), yellow(pokemonName), red("fight"), blue("battle"))
+	markSeen(speciesName(pokemon))
+	if pokemon.Shiny {
+		fmt.Println(boldYellow("It sparkles, it's a shiny pokemon!"))
+	}
+	fmt.Printf("You found a level %d %s%s!\nYou are now able to catch %s using the %s command\nor you can %s it using the %s command\n", pokemon.Level, yellow(pokemon.Name), variantTag(pokemon), blue("catch"), yellow(pokemonName), red("fight"), blue("battle"))
 	catchablePokemon[pokemonName] = pokemon
 	return nil
 }
 
 func commandPokedex(_ *Config, _ string) error {
 	fmt.Println(orange("Your Pokedex:"))
-	for _, pokemon := range PokeDex {
-		fmt.Printf("- %s\n", pokemon.Name)
+	for _, key := range sortedKeys() {
+		pokemon := PokeDex[key]
+		fmt.Printf("- %s%s level %d\n", label(pokemon), variantTag(pokemon), pokemon.Level)
 	}
 	return nil
 }
-func commandInspect(_ *Config, pokemonName string) error {
-	pokemon, ok := PokeDex[pokemonName]
+func commandInspect(_ *Config, ref string) error {
+	key, ok := findPokemon(ref)
 	if !ok {
-		fmt.Println("You have not yet caught this pokemon")
 		return nil
 	}
+	pokemon := PokeDex[key]
 	fmt.Println("stats:")
+	fmt.Printf("%s %s\n%s %s\n", blue("id:"), "#"+pokemon.ID, blue("nickname:"), displayName(pokemon))
 	fmt.Printf("%s %s\n%s %d\n%s %d\n%s %d\n%s %d\n", blue("name:"), yellow(pokemon.Name), green("height:"), pokemon.Height, orange("weight:"), pokemon.Weight, boldGreen("hp:"), pokemon.Hp, boldRed("attack:"), pokemon.Attack)
 	fmt.Printf("%s %d\n%s %d\n%s %d\n%s %d\n%s %d\n", blue("defense:"), pokemon.Defense, boldYellow("level:"), pokemon.Level, boldRed("special attack:"), pokemon.SpecialAttack, blue("special defense:"), pokemon.SpecialDefense, green("speed:"), pokemon.Speed)
+	fmt.Printf("%s %d\n", boldYellow("experience:"), pokemon.Experience)
+	if pokemon.Name != speciesName(pokemon) {
+		fmt.Printf("%s %s\n", boldYellow("form:"), pokemon.Name)
+	}
+	if pokemon.Gender != "" {
+		fmt.Printf("%s %s\n", boldYellow("gender:"), pokemon.Gender)
+	}
+	if pokemon.Shiny {
+		fmt.Println(boldYellow("shiny ★"))
+	}
+	if pokemon.Ability == "" {
+		fmt.Printf("%s none\n", boldYellow("ability:"))
+	} else if hiddenAbility(pokemon) {
+		fmt.Printf("%s %s %s\n", boldYellow("ability:"), cyan(pokemon.Ability), orange("(hidden)"))
+	} else {
+		fmt.Printf("%s %s\n", boldYellow("ability:"), cyan(pokemon.Ability))
+	}
 	fmt.Println(boldYellow("type:"))
 	for _, t := range pokemon.Types {
 		fmt.Printf("- %v\n", t.Type.Name)
 	}
-	return nil
+	if pokemon.HeldItem != "" {
+		fmt.Printf("%s %s\n", boldYellow("held item:"), cyan(pokemon.HeldItem))
+	}
+	printStatBreakdown(pokemon)
+	return printDexEntry(speciesName(pokemon))
 }
 
 func cleanInput(text string) []string {
@@ -391,7 +357,14 @@ func commandHelp(cfg *Config, _ string) error {
 	return nil
 }
 
-func commandMap(cfg *Config, _ string) error {
+func commandMap(cfg *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) > 0 && fields[0] == "--region" {
+		if len(fields) < 2 {
+			return fmt.Errorf("usage: map --region <region>")
+		}
+		return commandRegionMap(fields[1])
+	}
 	var url string
 	if cfg.Next == "" {
 		url = "https://pokeapi.co/api/v2/location-area?offset=0&limit=20"
@@ -407,9 +380,7 @@ func commandMap(cfg *Config, _ string) error {
 	if err != nil {
 		return err
 	}
-	for _, location := range allLocations.Results {
-		fmt.Println(location.Name)
-	}
+	printAreas(allLocations)
 	cfg.Next = allLocations.Next
 	cfg.Previous = allLocations.Previous
 	return nil
@@ -433,9 +404,7 @@ func commandMapb(cfg *Config, _ string) error {
 	if err != nil {
 		return err
 	}
-	for _, location := range allLocations.Results {
-		fmt.Println(location.Name)
-	}
+	printAreas(allLocations)
 	cfg.Next = allLocations.Next
 	cfg.Previous = allLocations.Previous
 	return nil
@@ -465,6 +434,18 @@ func GetData(c *pokecache.Cache, url string) ([]byte, error) {
 }
 
 func commandExplore(_ *Config, nameLocation string) error {
+	nameLocation, err := areaOrCurrent(nameLocation)
+	if err != nil {
+		return err
+	}
+	region, ok, err := areaAccessible(nameLocation)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Printf("%s lies in %s, you need more badges to go there\n", orange(nameLocation), red(region))
+		return nil
+	}
 	url := "https://pokeapi.co/api/v2/location-area/" + nameLocation + "/"
 	data, err := GetData(cache, url)
 	if err != nil {
@@ -477,78 +458,78 @@ func commandExplore(_ *Config, nameLocation string) error {
 	}
 	for _, pokemon := range area.PokemonEncounters {
 		fmt.Println(yellow(pokemon.Pokemon.Name))
+		species, err := encounterSpecies(pokemon.Pokemon.URL)
+		if err != nil {
+			return err
+		}
+		markSeen(species)
 	}
 	return nil
 }
 
-func commandCatch(_ *Config, pokemonName string) error {
+// commandCatch throws a ball at a pokemon found with the find command, catch <pokemon> --ball great picks the ball.
+func commandCatch(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	pokemonName, ball := "", "poke-ball"
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "--ball" && i+1 < len(fields) {
+			ball = ballName(fields[i+1])
+			i++
+		} else {
+			pokemonName = fields[i]
+		}
+	}
 	pokemon, ok := catchablePokemon[pokemonName]
 	if !ok {
 		fmt.Printf("You have not yet found %s or the pokemon does not exist\n", yellow(pokemonName))
 		return nil
 	}
-	fmt.Printf("Throwing a Pokeball at %s...\n", yellow(pokemonName))
-	const (
-		MaxBaseExp = 635.0 // highest known base experience (e.g. Blissey)
-		MinChance  = 25    // minimum capture chance %
-		MaxChance  = 90    // maximum capture chance %
-	)
-
-	// Normalize base experience into a range and invert
-	chance := MaxChance - int((float64(pokemon.BaseExperience)/MaxBaseExp)*float64(MaxChance-MinChance))
-
-	// Clamp to avoid going below MinChance
-	if chance < MinChance {
-		chance = MinChance
-	}
-	catchSucces := rand.Intn(100) < chance
-	if catchSucces {
-		fmt.Printf("%s was caught!\n", yellow(pokemonName))
-	} else {
-		fmt.Printf("%s escaped!\n", yellow(pokemonName))
+	if !readyBall(ball) {
 		return nil
 	}
-	pokemon.Level = 1
-	for _, stat := range pokemon.Stats {
-		if stat.StatInfo.Name == "hp" {
-			pokemon.MaxHp = stat.BaseStat
-		}
+	rate, err := captureRate(pokemon)
+	if err != nil {
+		return err
 	}
+	// outside of battle the pokemon is at full health, weakening it in a battle first makes it easier to catch
 	resetStats(&pokemon)
-	PokeDex[pokemonName] = pokemon
-	return nil
+	takeItem(ball)
+	fmt.Printf("Throwing a %s at %s...\n", cyan(ball), yellow(pokemonName))
+	modifier := balls[ball](ballThrow{target: pokemon, turn: 1})
+	shakes, caught := throwBall(captureValue(pokemon, rate, modifier, ""), rand.Intn)
+	printThrow(pokemonName, shakes, caught)
+	// either way the encounter is over, every find allows a single catch
+	delete(catchablePokemon, pokemonName)
+	if !caught {
+		fmt.Printf("%s fled! You have %d %s left\n", yellow(pokemonName), Bag[ball], ball)
+		return nil
+	}
+	return keepCaught(pokemon)
 }
 
 func resetStats(pokemon *PokemonInformation) {
-	for _, stat := range pokemon.Stats {
-		if pokemon.Level == 0 && stat.StatInfo.Name == "hp" {
-			pokemon.MaxHp = stat.BaseStat
-			pokemon.Level = 1
-		}
-		if stat.StatInfo.Name == "speed" {
-			pokemon.Speed = stat.BaseStat
-			continue
-		}
-		if stat.StatInfo.Name == "attack" {
-			pokemon.Attack = stat.BaseStat
-			continue
-		}
-		if stat.StatInfo.Name == "defense" {
-			pokemon.Defense = stat.BaseStat
-			continue
-		}
-		if stat.StatInfo.Name == "special-attack" {
-			pokemon.SpecialAttack = stat.BaseStat
-			continue
-		}
-		if stat.StatInfo.Name == "special-defense" {
-			pokemon.SpecialDefense = stat.BaseStat
-			continue
-		}
-	}
+	computeStats(pokemon)
 	pokemon.Hp = pokemon.MaxHp
 }
 
+type SaveData struct {
+	PokeDex          map[string]PokemonInformation
+	Party            []string
+	Difficulty       string
+	Money            int
+	DefeatedTrainers map[string]bool
+	Badges           []string
+	Bag              map[string]int
+	NextID           int
+	Boxes            []Box
+	DexSeen          map[string]bool
+	DexCaught        map[string]bool
+	DexLanguage      string
+	DexVariants      map[string]bool
+	ShinyRate        int
+	CurrentArea      string
+}
+
 func commandSave(_ *Config, _ string) error {
 	fmt.Printf("%s your progress\nDo %s shut off the program\n", boldGreen("Saving"), boldRed("not"))
 	filename := "save_" + time.Now().Format("20060102_150405") + ".bin"
@@ -558,7 +539,23 @@ func commandSave(_ *Config, _ string) error {
 	}
 	defer file.Close()
 	encoder := gob.NewEncoder(file)
-	err = encoder.Encode(PokeDex)
+	err = encoder.Encode(SaveData{
+		PokeDex:          PokeDex,
+		Party:            Party,
+		Difficulty:       Difficulty,
+		Money:            Money,
+		DefeatedTrainers: DefeatedTrainers,
+		Badges:           Badges,
+		Bag:              Bag,
+		NextID:           NextID,
+		Boxes:            Boxes,
+		DexSeen:          DexSeen,
+		DexCaught:        DexCaught,
+		DexLanguage:      DexLanguage,
+		DexVariants:      DexVariants,
+		ShinyRate:        ShinyRate,
+		CurrentArea:      CurrentArea,
+	})
 	if err != nil {
 		return err
 	}
@@ -575,6 +572,9 @@ func readSave() error {
 	var mostRecentTime time.Time
 	for _, file := range files {
 		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, "save_") {
+			continue
+		}
 		time_s := name[5:20]
 		t, err := time.Parse("20060102_150405", time_s)
 		if err != nil {
@@ -590,10 +590,58 @@ func readSave() error {
 	if err != nil {
 		return err
 	}
-	gob.NewDecoder(file).Decode(&PokeDex)
+	defer file.Close()
+	var save SaveData
+	err = gob.NewDecoder(file).Decode(&save)
+	if err == nil {
+		PokeDex = save.PokeDex
+		Party = save.Party
+		if save.Difficulty != "" {
+			Difficulty = save.Difficulty
+		}
+		Money = save.Money
+		DefeatedTrainers = save.DefeatedTrainers
+		Badges = save.Badges
+		if save.Bag != nil {
+			Bag = save.Bag
+		}
+		NextID = max(save.NextID, 1)
+		Boxes = save.Boxes
+		DexSeen = save.DexSeen
+		DexCaught = save.DexCaught
+		if save.DexLanguage != "" {
+			DexLanguage = save.DexLanguage
+		}
+		DexVariants = save.DexVariants
+		CurrentArea = save.CurrentArea
+		if save.ShinyRate > 0 {
+			ShinyRate = save.ShinyRate
+		}
+		upgradeSave()
+		return nil
+	}
+	// saves from before the party existed only hold the Pokedex map
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	err = gob.NewDecoder(file).Decode(&PokeDex)
+	if err != nil {
+		return err
+	}
+	fillParty()
+	upgradeSave()
 	return nil
 }
 
+// upgradeSave fills in what saves made by older versions are missing.
+func upgradeSave() {
+	migratePokedex()
+	ensureBoxed()
+	assignMissingAbilities()
+	registerCaughtSpecies()
+}
+
 func newAccount() error {
 	err := os.Mkdir("save_folder", 0755)
 	if err != nil {
@@ -637,7 +685,7 @@ func main() {
 
 		"map": {
 			name:        "map",
-			description: "Displays the locations in the pokemon world on the next page",
+			description: "Displays the locations in the pokemon world on the next page, map --region <region> shows one region as a tree",
 			callback:    commandMap,
 		},
 
@@ -649,13 +697,13 @@ func main() {
 
 		"explore": {
 			name:        "explore",
-			description: "Displays the pokemons that can be found in a specified location after the command",
+			description: "Displays the pokemons that can be found in an area, the current one when no area is given",
 			callback:    commandExplore,
 		},
 
 		"catch": {
 			name:        "catch",
-			description: "Command to try to catch a specified pokemon after the command",
+			description: "Command to try to catch a specified pokemon after the command, add --ball <type> to throw another ball",
 			callback:    commandCatch,
 		},
 
@@ -673,7 +721,7 @@ func main() {
 
 		"find": {
 			name:        "find",
-			description: "Use this command to find pokemon in an area",
+			description: "Finds a pokemon in the current area, add --method surf or a rod to fish",
 			callback:    commandFind,
 		},
 
@@ -685,10 +733,130 @@ func main() {
 
 		"battle": {
 			name:        "battle",
-			description: "Command to battle a given pokemon",
+			description: "Command to battle a given pokemon with your party, add --double for a 2v2 battle",
 			callback:    commandBattle,
 		},
 
+		"trade": {
+			name:        "trade",
+			description: "Trades a pokemon away and back, evolving pokemon that evolve by trading",
+			callback:    commandTrade,
+		},
+
+		"evolutions": {
+			name:        "evolutions",
+			description: "Displays the evolution chain of a given pokemon",
+			callback:    commandEvolutions,
+		},
+
+		"party": {
+			name:        "party",
+			description: "Displays your party, manage it with party add/remove <pokemon> or party order <pokemon>...",
+			callback:    commandParty,
+		},
+
+		"difficulty": {
+			name:        "difficulty",
+			description: "Displays or sets how smart opponents are: easy, normal or hard",
+			callback:    commandDifficulty,
+		},
+
+		"battles": {
+			name:        "battles",
+			description: "Lists your recorded battles and whether you won them",
+			callback:    commandBattles,
+		},
+
+		"replay": {
+			name:        "replay",
+			description: "Replays a recorded battle turn by turn, use the id from the battles command",
+			callback:    commandReplay,
+		},
+
+		"challenge": {
+			name:        "challenge",
+			description: "Battle a trainer or gym leader, without a name it lists who you can challenge",
+			callback:    commandChallenge,
+		},
+
+		"badges": {
+			name:        "badges",
+			description: "Displays your gym badges, level cap and the regions you can visit",
+			callback:    commandBadges,
+		},
+
+		"host": {
+			name:        "host",
+			description: "Waits for a friend to join a PvP battle against your party, optionally on a given port",
+			callback:    commandHost,
+		},
+
+		"join": {
+			name:        "join",
+			description: "Joins the PvP battle hosted at a given address",
+			callback:    commandJoin,
+		},
+
+		"bag": {
+			name:        "bag",
+			description: "Shows your items, bag give <pokemon> <berry> and bag take <pokemon> manage held items, bag use <item> <pokemon> uses evolution items",
+			callback:    commandBag,
+		},
+
+		"shop": {
+			name:        "shop",
+			description: "Lists the balls and items for sale, shop buy <item> [amount] buys them with your prize money",
+			callback:    commandShop,
+		},
+
+		"nickname": {
+			name:        "nickname",
+			description: "Gives a caught pokemon a nickname: nickname <pokemon> <name>",
+			callback:    commandNickname,
+		},
+
+		"release": {
+			name:        "release",
+			description: "Releases a caught pokemon after asking you to confirm",
+			callback:    commandRelease,
+		},
+
+		"box": {
+			name:        "box",
+			description: "Manages your PC boxes: box list [box], box move <pokemon> <box>, box deposit <pokemon> [box] and box withdraw <pokemon>",
+			callback:    commandBox,
+		},
+
+		"dex": {
+			name:        "dex",
+			description: "Shows dex completion, the entry of a species with dex <pokemon> or sets the language with dex language <code>",
+			callback:    commandDex,
+		},
+
+		"shiny": {
+			name:        "shiny",
+			description: "Shows or sets the shiny rate, shiny <n> makes 1 in n encounters shiny",
+			callback:    commandShiny,
+		},
+
+		"goto": {
+			name:        "goto",
+			description: "Travels to an area, other regions open up as you earn badges",
+			callback:    commandGoto,
+		},
+
+		"regions": {
+			name:        "regions",
+			description: "Lists the regions and which ones your badges unlock",
+			callback:    commandRegions,
+		},
+
+		"locations": {
+			name:        "locations",
+			description: "Lists the locations of a region with their areas, the current region when none is given",
+			callback:    commandLocations,
+		},
+
 		"learnmove": {
 			name:        "learnmove",
 			description: "Command to learn a move wich can be used in battle",
@@ -696,7 +864,7 @@ func main() {
 		},
 	}
 	rl, err := readline.NewEx(&readline.Config{
-		Prompt:            "Pokedex > ",
+		Prompt:            prompt(),
 		HistorySearchFold: true, // case-insensitive history search
 		InterruptPrompt:   "^C",
 		EOFPrompt:         "exit",
@@ -736,10 +904,7 @@ func main() {
 		}
 
 		command := cleanedInput[0]
-		secondCommand := ""
-		if len(cleanedInput) > 1 {
-			secondCommand = cleanedInput[1]
-		}
+		secondCommand := strings.Join(cleanedInput[1:], " ")
 
 		if cmd, exists := supportedCommands[command]; exists {
 			if err := cmd.callback(cfg, secondCommand); err != nil {
@@ -748,5 +913,6 @@ func main() {
 		} else {
 			fmt.Println("Unknown command")
 		}
+		rl.SetPrompt(prompt())
 	}
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const maxPartySize = 6
+
+// Party holds the Pokedex keys of the pokemon that fight in battles, the first one leads.
+var Party []string
+
+func inParty(key string) bool {
+	for _, member := range Party {
+		if member == key {
+			return true
+		}
+	}
+	return false
+}
+
+// fillParty gives saves from before the party existed a party made of their first pokemon.
+func fillParty() {
+	names := make([]string, 0, len(PokeDex))
+	for name := range PokeDex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(Party) >= maxPartySize {
+			return
+		}
+		Party = append(Party, name)
+	}
+}
+
+func printParty() {
+	fmt.Println(orange("Your party:"))
+	if len(Party) == 0 {
+		fmt.Println("Your party is empty, add pokemon with party add <pokemon>")
+		return
+	}
+	for i, member := range Party {
+		pokemon := PokeDex[member]
+		fmt.Printf("%d. %s level %d\n", i+1, label(pokemon), pokemon.Level)
+	}
+}
+
+func commandParty(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		printParty()
+		return nil
+	}
+	switch fields[0] {
+	case "add":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: party add <pokemon>")
+		}
+		return boxWithdraw(fields[1])
+	case "remove":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: party remove <pokemon>")
+		}
+		return boxDeposit(fields[1], "")
+	case "order":
+		return partyOrder(fields[1:])
+	}
+	return fmt.Errorf("unknown party command %s, use add, remove or order", fields[0])
+}
+
+// partyOrder moves the given pokemon to the front in the given order, the rest keep their order.
+func partyOrder(refs []string) error {
+	if len(refs) == 0 {
+		return fmt.Errorf("usage: party order <pokemon> [pokemon...]")
+	}
+	ordered := []string{}
+	for _, ref := range refs {
+		key, ok := findPokemon(ref)
+		if !ok {
+			return nil
+		}
+		if !inParty(key) {
+			fmt.Printf("%s is not in your party\n", yellow(ref))
+			return nil
+		}
+		for _, o := range ordered {
+			if o == key {
+				return fmt.Errorf("%s is listed more than once", ref)
+			}
+		}
+		ordered = append(ordered, key)
+	}
+	for _, member := range Party {
+		found := false
+		for _, o := range ordered {
+			if o == member {
+				found = true
+			}
+		}
+		if !found {
+			ordered = append(ordered, member)
+		}
+	}
+	Party = ordered
+	printParty()
+	return nil
+}
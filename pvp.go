@@ -0,0 +1,551 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	pvpProtocolVersion = 2
+	defaultPvPPort     = "7777"
+)
+
+// pvpReadTimeout is how long either side waits for the other before giving up on the battle.
+var pvpReadTimeout = 5 * time.Minute
+
+// pvpPokemon is the team snapshot sent over the wire. It only holds what the trainer chose or rolled,
+// the host looks up stats and moves in PokeAPI itself so a peer can't make up its own.
+type pvpPokemon struct {
+	Name     string         `json:"name"`
+	Level    int            `json:"level"`
+	IVs      map[string]int `json:"ivs"`
+	EVs      map[string]int `json:"evs"`
+	Nature   string         `json:"nature,omitempty"`
+	Ability  string         `json:"ability,omitempty"`
+	HeldItem string         `json:"held_item,omitempty"`
+	Moves    []string       `json:"moves"`
+}
+
+type pvpStatus struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+	Hp    int    `json:"hp"`
+	MaxHp int    `json:"max_hp"`
+}
+
+// pvpState is what the host tells the client before it has to choose.
+type pvpState struct {
+	Team     []pvpStatus `json:"team"`
+	Active   int         `json:"active"`
+	Opponent pvpStatus   `json:"opponent"`
+	Field    string      `json:"field,omitempty"`
+}
+
+// pvpMessage is one line of the protocol, type says which of the other fields are set.
+type pvpMessage struct {
+	Version int          `json:"version"`
+	Type    string       `json:"type"` // hello, welcome, request, action, event, end or error
+	Name    string       `json:"name,omitempty"`
+	Team    []pvpPokemon `json:"team,omitempty"`
+	State   *pvpState    `json:"state,omitempty"`
+	Forced  bool         `json:"forced,omitempty"`
+	Move    string       `json:"move,omitempty"`
+	Switch  *int         `json:"switch,omitempty"`
+	Event   *battleEvent `json:"event,omitempty"`
+	Winner  string       `json:"winner,omitempty"` // host or client
+	Error   string       `json:"error,omitempty"`
+}
+
+type pvpConn struct {
+	conn    net.Conn
+	encoder *json.Encoder
+	decoder *json.Decoder
+	// err is the first failed send, every later send returns it so a lost peer ends the battle
+	err error
+}
+
+func newPvPConn(conn net.Conn) *pvpConn {
+	return &pvpConn{conn: conn, encoder: json.NewEncoder(conn), decoder: json.NewDecoder(conn)}
+}
+
+func (c *pvpConn) send(msg pvpMessage) error {
+	if c.err != nil {
+		return c.err
+	}
+	msg.Version = pvpProtocolVersion
+	err := c.conn.SetWriteDeadline(time.Now().Add(pvpReadTimeout))
+	if err == nil {
+		err = c.encoder.Encode(msg)
+	}
+	if err != nil {
+		c.err = fmt.Errorf("connection lost: %w", err)
+	}
+	return c.err
+}
+
+// receive reads the next message and checks its version and, unless expected is empty, its type.
+func (c *pvpConn) receive(expected string) (pvpMessage, error) {
+	var msg pvpMessage
+	err := c.conn.SetReadDeadline(time.Now().Add(pvpReadTimeout))
+	if err != nil {
+		return pvpMessage{}, err
+	}
+	err = c.decoder.Decode(&msg)
+	if err != nil {
+		return pvpMessage{}, fmt.Errorf("connection lost: %w", err)
+	}
+	if msg.Type == "error" {
+		return pvpMessage{}, fmt.Errorf("the other trainer reported: %s", msg.Error)
+	}
+	if msg.Version != pvpProtocolVersion {
+		err = fmt.Errorf("protocol version %d is not supported, expected %d", msg.Version, pvpProtocolVersion)
+		return pvpMessage{}, errors.Join(err, c.send(pvpMessage{Type: "error", Error: err.Error()}))
+	}
+	if expected != "" && msg.Type != expected {
+		return pvpMessage{}, fmt.Errorf("expected a %s message, got %s", expected, msg.Type)
+	}
+	return msg, nil
+}
+
+func toPvPPokemon(pokemon PokemonInformation) pvpPokemon {
+	p := pvpPokemon{
+		Name:     pokemon.Name,
+		Level:    pokemon.Level,
+		IVs:      pokemon.IVs,
+		EVs:      pokemon.EVs,
+		Nature:   pokemon.Nature.Name,
+		Ability:  pokemon.Ability,
+		HeldItem: pokemon.HeldItem,
+	}
+	for _, move := range movesOf(pokemon) {
+		p.Moves = append(p.Moves, move.Name)
+	}
+	return p
+}
+
+// checkTrainingValues makes sure the IVs and EVs a peer sent could have been rolled and earned.
+func checkTrainingValues(p pvpPokemon) error {
+	total := 0
+	for stat, iv := range p.IVs {
+		if iv < 0 || iv > maxIndividualValue {
+			return fmt.Errorf("%s has an IV of %d for %s", p.Name, iv, stat)
+		}
+	}
+	for stat, ev := range p.EVs {
+		if ev < 0 || ev > maxEffortPerStat {
+			return fmt.Errorf("%s has an EV of %d for %s", p.Name, ev, stat)
+		}
+		total += ev
+	}
+	if total > maxEffortTotal {
+		return fmt.Errorf("%s has %d EVs, more than %d", p.Name, total, maxEffortTotal)
+	}
+	return nil
+}
+
+func getNature(name string) (Nature, error) {
+	var nature Nature
+	err := getJSON("https://pokeapi.co/api/v2/nature/"+name, &nature)
+	if err != nil {
+		return Nature{}, fmt.Errorf("unknown nature %s", name)
+	}
+	return nature, nil
+}
+
+// fromPvPPokemon rebuilds a pokemon from PokeAPI, the stats are computed here and only moves it can learn are accepted.
+func fromPvPPokemon(p pvpPokemon) (PokemonInformation, error) {
+	if p.Level < 1 || p.Level > maxLevel {
+		return PokemonInformation{}, fmt.Errorf("%s can't be level %d", p.Name, p.Level)
+	}
+	err := checkTrainingValues(p)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	var pokemon PokemonInformation
+	err = getJSON("https://pokeapi.co/api/v2/pokemon/"+p.Name, &pokemon)
+	if err != nil {
+		return PokemonInformation{}, fmt.Errorf("unknown pokemon %s", p.Name)
+	}
+	pokemon.Level = p.Level
+	pokemon.IVs = p.IVs
+	pokemon.EVs = p.EVs
+	if p.Nature != "" {
+		pokemon.Nature, err = getNature(p.Nature)
+		if err != nil {
+			return PokemonInformation{}, err
+		}
+	}
+	pokemon.Ability = chooseAbility(pokemon)
+	for _, a := range pokemon.Abilities {
+		if a.Ability.Name == p.Ability {
+			pokemon.Ability = p.Ability
+		}
+	}
+	if holdable(p.HeldItem) {
+		pokemon.HeldItem = p.HeldItem
+	}
+	pokemon.Moves = make(map[string]Move)
+	for _, name := range p.Moves {
+		url := ""
+		for _, entry := range pokemon.PokemonMovesAPIEntries {
+			if entry.MoveInfo.Name == name {
+				url = entry.MoveInfo.URL
+			}
+		}
+		if url == "" {
+			return PokemonInformation{}, fmt.Errorf("%s can't learn %s", p.Name, name)
+		}
+		var move Move
+		err = getJSON(url, &move)
+		if err != nil {
+			return PokemonInformation{}, err
+		}
+		if !supportedMove(move) {
+			return PokemonInformation{}, fmt.Errorf("%s is not supported in battles", name)
+		}
+		pokemon.Moves[move.Name] = move
+	}
+	resetStats(&pokemon)
+	return pokemon, nil
+}
+
+// partyTeam snapshots the party at full health for a PvP battle.
+func partyTeam() []pvpPokemon {
+	team := []pvpPokemon{}
+	for _, key := range Party {
+		pokemon, ok := PokeDex[key]
+		if !ok {
+			continue
+		}
+		resetStats(&pokemon)
+		team = append(team, toPvPPokemon(pokemon))
+	}
+	return team
+}
+
+func pvpSide(name string, team []pvpPokemon) (*battleSide, error) {
+	if len(team) == 0 || len(team) > maxPartySize {
+		return nil, fmt.Errorf("%s sent a team of %d pokemon, a team needs 1 to %d", name, len(team), maxPartySize)
+	}
+	side := &battleSide{name: name}
+	for _, p := range team {
+		pokemon, err := fromPvPPokemon(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s sent an invalid team: %w", name, err)
+		}
+		side.members = append(side.members, &battler{pokemon: pokemon})
+	}
+	return side, nil
+}
+
+func status(pokemon PokemonInformation) pvpStatus {
+	return pvpStatus{Name: pokemon.Name, Level: pokemon.Level, Hp: max(pokemon.Hp, 0), MaxHp: pokemon.MaxHp}
+}
+
+func stateFor(b *battle, side *battleSide) *pvpState {
+	state := &pvpState{Active: side.active[0], Opponent: status(b.other(side).current().pokemon), Field: b.field.describe()}
+	for _, member := range side.members {
+		state.Team = append(state.Team, status(member.pokemon))
+	}
+	return state
+}
+
+// remotePlayer is the client of a PvP battle as the host sees it.
+type remotePlayer struct {
+	conn *pvpConn
+}
+
+// validSwitch reports whether the client may switch to index.
+func validSwitch(side *battleSide, index int) bool {
+	return index >= 0 && index < len(side.members) && !side.inBattle(index) && !side.members[index].fainted()
+}
+
+func (r remotePlayer) chooseAction(b *battle, side *battleSide, _ int) (battleAction, error) {
+	err := r.conn.send(pvpMessage{Type: "request", State: stateFor(b, side)})
+	if err != nil {
+		return battleAction{}, err
+	}
+	msg, err := r.conn.receive("action")
+	if err != nil {
+		return battleAction{}, err
+	}
+	active := side.current()
+	if msg.Switch != nil && validSwitch(side, *msg.Switch) {
+		return battleAction{side: side, actor: active, switchTo: *msg.Switch}, nil
+	}
+	move, ok := active.pokemon.Moves[msg.Move]
+	if !ok {
+		// the host is authoritative, an invalid choice becomes the first move
+		move = firstMove(active.pokemon)
+	}
+	return battleAction{side: side, actor: active, move: move, switchTo: -1}, nil
+}
+
+func (r remotePlayer) chooseReplacement(b *battle, side *battleSide, _ int) (int, error) {
+	err := r.conn.send(pvpMessage{Type: "request", State: stateFor(b, side), Forced: true})
+	if err != nil {
+		return -1, err
+	}
+	msg, err := r.conn.receive("action")
+	if err != nil {
+		return -1, err
+	}
+	if msg.Switch == nil || !validSwitch(side, *msg.Switch) {
+		return side.nextAvailable(), nil
+	}
+	return *msg.Switch, nil
+}
+
+// hostPvPBattle accepts the client's team and resolves every turn, it reports whether the host won.
+func hostPvPBattle(conn net.Conn, name string, team []pvpPokemon, source actionSource) (bool, error) {
+	c := newPvPConn(conn)
+	hello, err := c.receive("hello")
+	if err != nil {
+		return false, err
+	}
+	player, err := pvpSide(name, team)
+	if err != nil {
+		return false, errors.Join(err, c.send(pvpMessage{Type: "error", Error: "the host has no usable team"}))
+	}
+	opponent, err := pvpSide(hello.Name, hello.Team)
+	if err != nil {
+		return false, errors.Join(err, c.send(pvpMessage{Type: "error", Error: err.Error()}))
+	}
+	err = c.send(pvpMessage{Type: "welcome", Name: name, Team: team})
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("%s wants to battle!\n", boldYellow(hello.Name))
+	b := &battle{
+		player:         player,
+		opponent:       opponent,
+		friendly:       true,
+		playerSource:   source,
+		opponentSource: remotePlayer{conn: c},
+		levelled:       make(map[string]bool),
+		onEvent: func(event battleEvent) {
+			// a failed send is kept by the connection, the next request to the client returns it
+			c.send(pvpMessage{Type: "event", Event: &event})
+		},
+	}
+	seed := time.Now().UnixNano()
+	battleRand = rand.New(rand.NewSource(seed))
+	b.log = startBattleLog(b, seed)
+	won, err := b.run()
+	b.log.close()
+	if err != nil {
+		return false, err
+	}
+	if c.err != nil {
+		// the client missed events of the last turn, it can't agree on the result
+		return false, c.err
+	}
+	winner := "client"
+	if won {
+		winner = "host"
+	}
+	return won, c.send(pvpMessage{Type: "end", Winner: winner})
+}
+
+// pvpChooser makes the client's decisions from the state the host sends, switchTo is -1 to use move.
+type pvpChooser interface {
+	chooseAction(state pvpState, team []pvpPokemon) (move string, switchTo int)
+	chooseReplacement(state pvpState, team []pvpPokemon) int
+}
+
+type consolePvPChooser struct {
+	scanner *bufio.Scanner
+}
+
+func printPvPState(state pvpState) {
+	if state.Field != "" {
+		fmt.Println(cyan(state.Field))
+	}
+	active := state.Team[state.Active]
+	fmt.Printf("%s level %d %d/%d hp vs %s level %d %d/%d hp\n", yellow(active.Name), active.Level, active.Hp, active.MaxHp, yellow(state.Opponent.Name), state.Opponent.Level, state.Opponent.Hp, state.Opponent.MaxHp)
+}
+
+// pvpSwitchIndex finds a pokemon of the client's team that can be sent in, or -1.
+func pvpSwitchIndex(state pvpState, name string) int {
+	for i, member := range state.Team {
+		if member.Name == name && i != state.Active && member.Hp > 0 {
+			return i
+		}
+	}
+	fmt.Printf("%s can't be sent in\n", yellow(name))
+	return -1
+}
+
+func (c consolePvPChooser) chooseAction(state pvpState, team []pvpPokemon) (string, int) {
+	printPvPState(state)
+	for _, move := range team[state.Active].Moves {
+		fmt.Println(cyan(move))
+	}
+	fmt.Printf("choose a move to play or switch <pokemon>:")
+	for c.scanner.Scan() {
+		input := strings.TrimSpace(strings.ToLower(c.scanner.Text()))
+		if name, ok := strings.CutPrefix(input, "switch "); ok {
+			if index := pvpSwitchIndex(state, strings.TrimSpace(name)); index >= 0 {
+				return "", index
+			}
+		} else {
+			for _, move := range team[state.Active].Moves {
+				if move == input {
+					return input, -1
+				}
+			}
+		}
+		fmt.Printf("\nchoose a move to play or switch <pokemon>:")
+	}
+	return "", -1
+}
+
+func (c consolePvPChooser) chooseReplacement(state pvpState, _ []pvpPokemon) int {
+	for {
+		fmt.Printf("Choose your next pokemon:")
+		if !c.scanner.Scan() {
+			return -1
+		}
+		if index := pvpSwitchIndex(state, strings.TrimSpace(strings.ToLower(c.scanner.Text()))); index >= 0 {
+			return index
+		}
+	}
+}
+
+// flipEvent turns an event the host recorded into the client's point of view.
+func flipEvent(event battleEvent) battleEvent {
+	sides := map[string]string{"player": "opponent", "opponent": "player"}
+	outcomes := map[string]string{"won": "lost", "lost": "won"}
+	if side, ok := sides[event.Side]; ok {
+		event.Side = side
+	}
+	if outcome, ok := outcomes[event.Outcome]; ok {
+		event.Outcome = outcome
+	}
+	return event
+}
+
+// joinPvPBattle sends the team to the host and answers its requests, it reports whether the client won.
+func joinPvPBattle(conn net.Conn, name string, team []pvpPokemon, chooser pvpChooser) (bool, error) {
+	c := newPvPConn(conn)
+	err := c.send(pvpMessage{Type: "hello", Name: name, Team: team})
+	if err != nil {
+		return false, err
+	}
+	welcome, err := c.receive("welcome")
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("You are battling %s!\n", boldYellow(welcome.Name))
+	for {
+		msg, err := c.receive("")
+		if err != nil {
+			return false, err
+		}
+		switch msg.Type {
+		case "event":
+			// the outcome is reported once the host ends the battle
+			if msg.Event != nil && msg.Event.Kind != "outcome" {
+				renderEvent(flipEvent(*msg.Event))
+			}
+		case "request":
+			if msg.State == nil || msg.State.Active < 0 || msg.State.Active >= len(team) {
+				return false, fmt.Errorf("the host sent an invalid request")
+			}
+			reply := pvpMessage{Type: "action"}
+			if msg.Forced {
+				index := chooser.chooseReplacement(*msg.State, team)
+				reply.Switch = &index
+			} else {
+				move, switchTo := chooser.chooseAction(*msg.State, team)
+				reply.Move = move
+				if switchTo >= 0 {
+					reply.Switch = &switchTo
+				}
+			}
+			err = c.send(reply)
+			if err != nil {
+				return false, err
+			}
+		case "end":
+			return msg.Winner == "client", nil
+		default:
+			return false, fmt.Errorf("unexpected %s message from the host", msg.Type)
+		}
+	}
+}
+
+func trainerName() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "trainer"
+}
+
+func commandHost(_ *Config, port string) error {
+	team := partyTeam()
+	if len(team) == 0 {
+		fmt.Printf("You have no pokemon in your party to fight with\nGo catch some pokemon!\n")
+		return nil
+	}
+	if port == "" {
+		port = defaultPvPPort
+	}
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Waiting for a challenger on port %s, they can connect with %s\n", orange(port), blue("join <your-address>:"+port))
+	conn, err := listener.Accept()
+	listener.Close()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	won, err := hostPvPBattle(conn, trainerName(), team, consolePlayer{scanner: bufio.NewScanner(os.Stdin)})
+	if err != nil {
+		return err
+	}
+	if won {
+		fmt.Println(boldGreen("You won the PvP battle!"))
+	} else {
+		fmt.Println(boldRed("You lost the PvP battle!"))
+	}
+	return nil
+}
+
+func commandJoin(_ *Config, addr string) error {
+	if addr == "" {
+		return fmt.Errorf("usage: join <address>[:port]")
+	}
+	team := partyTeam()
+	if len(team) == 0 {
+		fmt.Printf("You have no pokemon in your party to fight with\nGo catch some pokemon!\n")
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultPvPPort)
+	}
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	won, err := joinPvPBattle(conn, trainerName(), team, consolePvPChooser{scanner: bufio.NewScanner(os.Stdin)})
+	if err != nil {
+		return err
+	}
+	if won {
+		fmt.Println(boldGreen("You won the PvP battle!"))
+	} else {
+		fmt.Println(boldRed("You lost the PvP battle!"))
+	}
+	return nil
+}
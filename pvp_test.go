@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// firstMoveSource always plays its first move, like a player who never thinks.
+type firstMoveSource struct{}
+
+func (firstMoveSource) chooseAction(_ *battle, side *battleSide, _ int) (battleAction, error) {
+	active := side.current()
+	return battleAction{side: side, actor: active, move: firstMove(active.pokemon), switchTo: -1}, nil
+}
+
+func (firstMoveSource) chooseReplacement(_ *battle, side *battleSide, _ int) (int, error) {
+	return side.nextAvailable(), nil
+}
+
+type firstMoveChooser struct{}
+
+func (firstMoveChooser) chooseAction(state pvpState, team []pvpPokemon) (string, int) {
+	return team[state.Active].Moves[0], -1
+}
+
+func (firstMoveChooser) chooseReplacement(state pvpState, _ []pvpPokemon) int {
+	for i, member := range state.Team {
+		if member.Hp > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// pvpTestTeam caches a pokemon of each type with one move of that type and returns the team as it is sent.
+func pvpTestTeam(types ...string) []pvpPokemon {
+	team := []pvpPokemon{}
+	for _, t := range types {
+		cachePokemon(t+"-mon", []string{t}, t+"-attack")
+		cacheMove(t+"-attack", t, "physical", 60)
+		team = append(team, pvpPokemon{Name: t + "-mon", Level: 20, Moves: []string{t + "-attack"}})
+	}
+	return team
+}
+
+func inTempDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestPvPBattleOverLocalhost(t *testing.T) {
+	inTempDir(t)
+	useTestCache(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	type result struct {
+		won bool
+		err error
+	}
+	hostResult := make(chan result)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			hostResult <- result{err: err}
+			return
+		}
+		defer conn.Close()
+		// water beats fire twice over, so the host has to win
+		won, err := hostPvPBattle(conn, "host", pvpTestTeam("water", "water"), firstMoveSource{})
+		hostResult <- result{won: won, err: err}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	clientWon, err := joinPvPBattle(conn, "client", pvpTestTeam("fire", "fire"), firstMoveChooser{})
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	host := <-hostResult
+	if host.err != nil {
+		t.Fatalf("host: %v", host.err)
+	}
+	if !host.won || clientWon {
+		t.Errorf("expected the host to win, host won: %v, client won: %v", host.won, clientWon)
+	}
+}
+
+func TestPvPRejectsOtherProtocolVersion(t *testing.T) {
+	useTestCache(t)
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	go func() {
+		conn := newPvPConn(client)
+		conn.encoder.Encode(pvpMessage{Version: pvpProtocolVersion + 1, Type: "hello", Name: "future", Team: pvpTestTeam("fire")})
+		conn.decoder.Decode(&pvpMessage{})
+	}()
+	_, err := hostPvPBattle(server, "host", pvpTestTeam("water"), firstMoveSource{})
+	if err == nil {
+		t.Error("expected a newer protocol version to be rejected")
+	}
+}
+
+func TestPvPSilentPeerTimesOut(t *testing.T) {
+	useTestCache(t)
+	previous := pvpReadTimeout
+	pvpReadTimeout = 50 * time.Millisecond
+	defer func() { pvpReadTimeout = previous }()
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	_, err := hostPvPBattle(server, "host", pvpTestTeam("water"), firstMoveSource{})
+	if err == nil || !strings.Contains(err.Error(), "connection lost") {
+		t.Errorf("expected the host to give up on a silent client, got %v", err)
+	}
+}
+
+func TestFromPvPPokemonRecomputesStats(t *testing.T) {
+	useTestCache(t)
+	cachePokemon("water-mon", []string{"water"}, "water-attack")
+	cacheMove("water-attack", "water", "physical", 60)
+	cases := []struct {
+		name     string
+		sent     pvpPokemon
+		expected string
+	}{
+		{name: "valid", sent: pvpPokemon{Name: "water-mon", Level: 20, IVs: map[string]int{"attack": 31}, Moves: []string{"water-attack"}}},
+		{name: "level too high", sent: pvpPokemon{Name: "water-mon", Level: 101}, expected: "level"},
+		{name: "iv too high", sent: pvpPokemon{Name: "water-mon", Level: 20, IVs: map[string]int{"attack": 99}}, expected: "IV"},
+		{name: "too many evs", sent: pvpPokemon{Name: "water-mon", Level: 20, EVs: map[string]int{"attack": 252, "speed": 252, "hp": 252}}, expected: "EVs"},
+		{name: "unlearnable move", sent: pvpPokemon{Name: "water-mon", Level: 20, Moves: []string{"fire-attack"}}, expected: "can't learn"},
+	}
+	for _, c := range cases {
+		pokemon, err := fromPvPPokemon(c.sent)
+		if c.expected != "" {
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("%s: expected an error about %q, got %v", c.name, c.expected, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		expected := PokemonInformation{Level: 20, IVs: c.sent.IVs, Stats: pokemon.Stats}
+		computeStats(&expected)
+		if pokemon.Attack != expected.Attack || pokemon.Hp != expected.MaxHp || pokemon.Moves["water-attack"].Power != 60 {
+			t.Errorf("%s: expected stats computed by the host, got attack %d and hp %d", c.name, pokemon.Attack, pokemon.Hp)
+		}
+	}
+}
+
+func TestPvPHostNoticesLostClient(t *testing.T) {
+	inTempDir(t)
+	useTestCache(t)
+	server, client := net.Pipe()
+	defer server.Close()
+	go func() {
+		conn := newPvPConn(client)
+		conn.send(pvpMessage{Type: "hello", Name: "client", Team: pvpTestTeam("fire")})
+		conn.receive("welcome")
+		client.Close()
+	}()
+	start := time.Now()
+	_, err := hostPvPBattle(server, "host", pvpTestTeam("water"), firstMoveSource{})
+	if err == nil || !strings.Contains(err.Error(), "connection lost") {
+		t.Errorf("expected the host to notice the client is gone, got %v", err)
+	}
+	if time.Since(start) > pvpReadTimeout/2 {
+		t.Error("expected the lost client to be noticed without waiting for the read timeout")
+	}
+	c := newPvPConn(server)
+	c.err = errors.New("connection lost: closed")
+	if err := c.send(pvpMessage{Type: "event"}); err != c.err {
+		t.Errorf("expected a failed connection to keep failing sends, got %v", err)
+	}
+}
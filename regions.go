@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func getRegion(name string) (Region, error) {
+	var region Region
+	err := getJSON("https://pokeapi.co/api/v2/region/"+name, &region)
+	if err != nil {
+		return Region{}, fmt.Errorf("unknown region %s, use regions to list them", name)
+	}
+	return region, nil
+}
+
+// regionLocations fetches every location of the region, with the areas that lie in it.
+func regionLocations(region Region) ([]Location, error) {
+	locations := []Location{}
+	for _, resource := range region.Locations {
+		var location Location
+		err := getJSON(resource.URL, &location)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// regionTree draws the locations with their areas below them, locations without areas have no wild pokemon to show.
+func regionTree(region string, locations []Location, current string) []string {
+	lines := []string{yellow(region)}
+	shown := []Location{}
+	for _, location := range locations {
+		if len(location.Areas) > 0 {
+			shown = append(shown, location)
+		}
+	}
+	for i, location := range shown {
+		branch, indent := "├─ ", "│  "
+		if i == len(shown)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		lines = append(lines, branch+orange(location.Name))
+		for j, area := range location.Areas {
+			leaf := "├─ "
+			if j == len(location.Areas)-1 {
+				leaf = "└─ "
+			}
+			line := indent + leaf + area.Name
+			if area.Name == current {
+				line += " " + boldGreen("<- you are here")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func commandRegionMap(name string) error {
+	region, err := getRegion(name)
+	if err != nil {
+		return err
+	}
+	locations, err := regionLocations(region)
+	if err != nil {
+		return err
+	}
+	for _, line := range regionTree(region.Name, locations, CurrentArea) {
+		fmt.Println(line)
+	}
+	if !regionUnlocked(region.Name) {
+		fmt.Println(red("You need more badges to travel here"))
+	}
+	return nil
+}
+
+func commandRegions(_ *Config, _ string) error {
+	var regions NamedAPIResourceList
+	err := getJSON("https://pokeapi.co/api/v2/region", &regions)
+	if err != nil {
+		return err
+	}
+	current := currentRegion()
+	fmt.Println(orange("Regions:"))
+	for _, region := range regions.Results {
+		switch {
+		case region.Name == current:
+			fmt.Printf("- %s %s\n", boldGreen(region.Name), boldGreen("<- you are here"))
+		case regionUnlocked(region.Name):
+			fmt.Printf("- %s\n", green(region.Name))
+		default:
+			fmt.Printf("- %s (locked)\n", red(region.Name))
+		}
+	}
+	return nil
+}
+
+func commandLocations(_ *Config, name string) error {
+	if name == "" {
+		name = currentRegion()
+		if name == "" {
+			return fmt.Errorf("usage: locations <region>, use regions to list them")
+		}
+	}
+	region, err := getRegion(name)
+	if err != nil {
+		return err
+	}
+	locations, err := regionLocations(region)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", orange("Locations of"), yellow(region.Name))
+	for _, location := range locations {
+		areas := []string{}
+		for _, area := range location.Areas {
+			areas = append(areas, area.Name)
+		}
+		if len(areas) == 0 {
+			fmt.Printf("- %s\n", location.Name)
+		} else {
+			fmt.Printf("- %s: %s\n", orange(location.Name), strings.Join(areas, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegionTree(t *testing.T) {
+	locations := []Location{
+		{Name: "pallet-town"},
+		{Name: "viridian-forest", Areas: []NamedAPIResource{{Name: "viridian-forest-area"}}},
+		{Name: "mt-moon", Areas: []NamedAPIResource{{Name: "mt-moon-1f"}, {Name: "mt-moon-b1f"}}},
+	}
+	lines := regionTree("kanto", locations, "mt-moon-1f")
+	cases := []struct {
+		name     string
+		expected string
+		here     bool
+	}{
+		{name: "region", expected: "kanto"},
+		{name: "location", expected: "├─ viridian-forest"},
+		{name: "only area", expected: "│  └─ viridian-forest-area"},
+		{name: "last location", expected: "└─ mt-moon"},
+		{name: "current area", expected: "   ├─ mt-moon-1f", here: true},
+		{name: "last area", expected: "   └─ mt-moon-b1f"},
+	}
+	if len(lines) != len(cases) {
+		t.Fatalf("expected %d lines without the empty pallet-town, got %q", len(cases), lines)
+	}
+	for i, c := range cases {
+		if !strings.Contains(lines[i], c.expected) {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, lines[i])
+		}
+		if here := strings.Contains(lines[i], "you are here"); here != c.here {
+			t.Errorf("%s: expected marked as here to be %v, got %v", c.name, c.here, here)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// shopStock is what the shop sells, prices come from the PokeAPI item cost.
+var shopStock = []string{
+	"poke-ball",
+	"great-ball",
+	"ultra-ball",
+	"net-ball",
+	"quick-ball",
+	"timer-ball",
+	"nest-ball",
+	"repeat-ball",
+	"potion",
+	"super-potion",
+	"hyper-potion",
+	"antidote",
+	"paralyze-heal",
+	"awakening",
+	"burn-heal",
+	"ice-heal",
+	"full-heal",
+	"x-attack",
+	"x-defense",
+	"x-speed",
+}
+
+func inStock(name string) bool {
+	for _, item := range shopStock {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+func printShop() error {
+	fmt.Printf("%s you have %s\n", orange("Welcome to the shop!"), boldYellow(fmt.Sprintf("₽%d", Money)))
+	for _, name := range shopStock {
+		item, err := getItem(name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("- %s ₽%d (you have %d)\n", cyan(name), item.Cost, Bag[name])
+	}
+	fmt.Printf("Buy with %s\n", blue("shop buy <item> [amount]"))
+	return nil
+}
+
+func buy(name string, amount int) error {
+	if !inStock(name) {
+		fmt.Printf("The shop does not sell %s\n", name)
+		return nil
+	}
+	item, err := getItem(name)
+	if err != nil {
+		return err
+	}
+	// checked before multiplying, a huge amount would overflow the total
+	if item.Cost > 0 && amount > Money/item.Cost {
+		fmt.Printf("A %s costs ₽%d, with ₽%d you can buy at most %d\n", name, item.Cost, Money, Money/item.Cost)
+		return nil
+	}
+	total := item.Cost * amount
+	Money -= total
+	addItem(name, amount)
+	fmt.Printf("You bought %d %s for ₽%d, you have %s left\n", amount, cyan(name), total, boldYellow(fmt.Sprintf("₽%d", Money)))
+	return nil
+}
+
+func commandShop(_ *Config, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return printShop()
+	}
+	if fields[0] != "buy" || len(fields) < 2 || len(fields) > 3 {
+		return fmt.Errorf("usage: shop buy <item> [amount]")
+	}
+	name := fields[1]
+	if _, isBall := balls[ballName(name)]; isBall && !inStock(name) {
+		name = ballName(name)
+	}
+	amount := 1
+	if len(fields) == 3 {
+		n, err := strconv.Atoi(fields[2])
+		if err != nil || n < 1 {
+			return fmt.Errorf("the amount has to be a positive number")
+		}
+		amount = n
+	}
+	return buy(name, amount)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBuy(t *testing.T) {
+	useTestCache(t)
+	cache.Add("https://pokeapi.co/api/v2/item/potion", []byte(`{"name": "potion", "cost": 200}`))
+	cases := []struct {
+		name          string
+		money         int
+		amount        int
+		expectedMoney int
+		expectedBag   int
+	}{
+		{name: "affordable", money: 1000, amount: 3, expectedMoney: 400, expectedBag: 3},
+		{name: "exactly affordable", money: 600, amount: 3, expectedMoney: 0, expectedBag: 3},
+		{name: "too expensive", money: 500, amount: 3, expectedMoney: 500, expectedBag: 0},
+		{name: "overflowing amount", money: 500, amount: math.MaxInt/200 + 2, expectedMoney: 500, expectedBag: 0},
+		{name: "largest amount", money: 1000, amount: math.MaxInt, expectedMoney: 1000, expectedBag: 0},
+	}
+	for _, c := range cases {
+		Money, Bag = c.money, map[string]int{}
+		err := buy("potion", c.amount)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if Money != c.expectedMoney || Bag["potion"] != c.expectedBag {
+			t.Errorf("%s: expected ₽%d and %d potions, got ₽%d and %d potions", c.name, c.expectedMoney, c.expectedBag, Money, Bag["potion"])
+		}
+	}
+	Money, Bag = 0, nil
+}
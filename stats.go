@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
+
+const (
+	maxIndividualValue = 31
+	maxEffortPerStat   = 252
+	maxEffortTotal     = 510
+)
+
+type Nature struct {
+	Name          string   `json:"name"`
+	IncreasedStat StatInfo `json:"increased_stat"`
+	DecreasedStat StatInfo `json:"decreased_stat"`
+}
+
+type NatureListResponse struct {
+	Results []NamedAPIResource `json:"results"`
+}
+
+func randomNature() (Nature, error) {
+	data, err := GetData(cache, "https://pokeapi.co/api/v2/nature?limit=25")
+	if err != nil {
+		return Nature{}, err
+	}
+	var natures NatureListResponse
+	err = json.Unmarshal(data, &natures)
+	if err != nil {
+		return Nature{}, err
+	}
+	if len(natures.Results) == 0 {
+		return Nature{}, fmt.Errorf("no natures available")
+	}
+	data, err = GetData(cache, natures.Results[rand.Intn(len(natures.Results))].URL)
+	if err != nil {
+		return Nature{}, err
+	}
+	var nature Nature
+	err = json.Unmarshal(data, &nature)
+	if err != nil {
+		return Nature{}, err
+	}
+	return nature, nil
+}
+
+func rollIndividualValues(pokemon PokemonInformation) map[string]int {
+	ivs := make(map[string]int)
+	for _, stat := range pokemon.Stats {
+		ivs[stat.StatInfo.Name] = rand.Intn(maxIndividualValue + 1)
+	}
+	return ivs
+}
+
+// natureMultiplier returns the nature bonus as a percentage so the stat formula can stay in integers.
+func natureMultiplier(nature Nature, statName string) int {
+	switch {
+	case nature.IncreasedStat.Name == nature.DecreasedStat.Name:
+		return 100
+	case nature.IncreasedStat.Name == statName:
+		return 110
+	case nature.DecreasedStat.Name == statName:
+		return 90
+	}
+	return 100
+}
+
+// calculateStat applies the standard stat formula from generation III onwards.
+func calculateStat(statName string, base, iv, ev, level int, nature Nature) int {
+	value := (2*base + iv + ev/4) * level / 100
+	if statName == "hp" {
+		return value + level + 10
+	}
+	return (value + 5) * natureMultiplier(nature, statName) / 100
+}
+
+// computeStats derives the battle stats from the base stats, IVs, EVs and nature at the pokemon's current level.
+func computeStats(pokemon *PokemonInformation) {
+	if pokemon.Level == 0 {
+		pokemon.Level = 1
+	}
+	for _, stat := range pokemon.Stats {
+		name := stat.StatInfo.Name
+		value := calculateStat(name, stat.BaseStat, pokemon.IVs[name], pokemon.EVs[name], pokemon.Level, pokemon.Nature)
+		switch name {
+		case "hp":
+			pokemon.MaxHp = value
+		case "attack":
+			pokemon.Attack = value
+		case "defense":
+			pokemon.Defense = value
+		case "special-attack":
+			pokemon.SpecialAttack = value
+		case "special-defense":
+			pokemon.SpecialDefense = value
+		case "speed":
+			pokemon.Speed = value
+		}
+	}
+}
+
+// gainEffortValues awards the defeated pokemon's effort yield, respecting the per stat and total caps.
+func gainEffortValues(pokemon *PokemonInformation, defeated PokemonInformation) {
+	if pokemon.EVs == nil {
+		pokemon.EVs = make(map[string]int)
+	}
+	total := 0
+	for _, ev := range pokemon.EVs {
+		total += ev
+	}
+	for _, stat := range defeated.Stats {
+		gain := stat.Effort
+		name := stat.StatInfo.Name
+		if pokemon.EVs[name]+gain > maxEffortPerStat {
+			gain = maxEffortPerStat - pokemon.EVs[name]
+		}
+		if total+gain > maxEffortTotal {
+			gain = maxEffortTotal - total
+		}
+		if gain <= 0 {
+			continue
+		}
+		pokemon.EVs[name] += gain
+		total += gain
+		fmt.Printf("%s gained %d %s effort\n", yellow(pokemon.Name), gain, name)
+	}
+}
+
+func printStatBreakdown(pokemon PokemonInformation) {
+	nature := pokemon.Nature.Name
+	if nature == "" {
+		nature = "unknown"
+	}
+	fmt.Printf("%s %s\n", boldYellow("nature:"), nature)
+	fmt.Println(boldYellow("stat breakdown:"))
+	for _, stat := range pokemon.Stats {
+		name := stat.StatInfo.Name
+		marker := ""
+		switch natureMultiplier(pokemon.Nature, name) {
+		case 110:
+			marker = green(" (+)")
+		case 90:
+			marker = red(" (-)")
+		}
+		fmt.Printf("- %s: base %d, iv %d, ev %d%s\n", name, stat.BaseStat, pokemon.IVs[name], pokemon.EVs[name], marker)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCalculateStat(t *testing.T) {
+	adamant := Nature{Name: "adamant", IncreasedStat: StatInfo{Name: "attack"}, DecreasedStat: StatInfo{Name: "special-attack"}}
+	cases := []struct {
+		stat     string
+		base     int
+		iv       int
+		ev       int
+		expected int
+	}{
+		{stat: "hp", base: 108, iv: 24, ev: 74, expected: 289},
+		{stat: "attack", base: 130, iv: 12, ev: 195, expected: 279},
+		{stat: "defense", base: 95, iv: 30, ev: 86, expected: 192},
+		{stat: "special-attack", base: 80, iv: 16, ev: 48, expected: 135},
+	}
+	for _, c := range cases {
+		actual := calculateStat(c.stat, c.base, c.iv, c.ev, 78, adamant)
+		if actual != c.expected {
+			t.Errorf("%s: expected %d, got %d", c.stat, c.expected, actual)
+		}
+	}
+}
+
+func TestGainEffortValuesCaps(t *testing.T) {
+	pokemon := PokemonInformation{EVs: map[string]int{"attack": 251, "speed": 252, "defense": 5}}
+	defeated := PokemonInformation{Stats: []Stat{
+		{Effort: 2, StatInfo: StatInfo{Name: "attack"}},
+		{Effort: 3, StatInfo: StatInfo{Name: "hp"}},
+	}}
+	gainEffortValues(&pokemon, defeated)
+	if pokemon.EVs["attack"] != maxEffortPerStat {
+		t.Errorf("expected attack effort to stop at %d, got %d", maxEffortPerStat, pokemon.EVs["attack"])
+	}
+	if pokemon.EVs["hp"] != 1 {
+		t.Errorf("expected hp effort to stop at the %d total, got %d", maxEffortTotal, pokemon.EVs["hp"])
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed data/trainers.json
+var trainersData []byte
+
+type TrainerPokemon struct {
+	Species string   `json:"species"`
+	Level   int      `json:"level"`
+	Moves   []string `json:"moves"`
+}
+
+type Trainer struct {
+	Name  string           `json:"name"`
+	Title string           `json:"title"`
+	Prize int              `json:"prize"`
+	Team  []TrainerPokemon `json:"team"`
+}
+
+// Money is what the player earned from battles, DefeatedTrainers remembers who they have beaten.
+var Money int
+var DefeatedTrainers map[string]bool
+
+func loadTrainers() ([]Trainer, error) {
+	var trainers []Trainer
+	err := json.Unmarshal(trainersData, &trainers)
+	if err != nil {
+		return nil, err
+	}
+	return trainers, nil
+}
+
+func findTrainer(name string) (Trainer, bool, error) {
+	trainers, err := loadTrainers()
+	if err != nil {
+		return Trainer{}, false, err
+	}
+	for _, trainer := range trainers {
+		if trainer.Name == name {
+			return trainer, true, nil
+		}
+	}
+	return Trainer{}, false, nil
+}
+
+// newPokemon fetches a species at a level with the given moves, without moves it learns random ones.
+func newPokemon(species string, level int, moveNames []string) (PokemonInformation, error) {
+	data, err := GetData(cache, "https://pokeapi.co/api/v2/pokemon/"+species)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	var pokemon PokemonInformation
+	err = json.Unmarshal(data, &pokemon)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	pokemon.Level = level
+	pokemon.Ability = chooseAbility(pokemon)
+	pokemon.Moves = make(map[string]Move)
+	for _, name := range moveNames {
+		moveData, err := GetData(cache, "https://pokeapi.co/api/v2/move/"+name)
+		if err != nil {
+			return PokemonInformation{}, err
+		}
+		var move Move
+		err = json.Unmarshal(moveData, &move)
+		if err != nil {
+			return PokemonInformation{}, err
+		}
+		pokemon.Moves[move.Name] = move
+	}
+	if len(pokemon.Moves) == 0 {
+		_, err = simpelLearnMove(&pokemon)
+		if err != nil {
+			return PokemonInformation{}, err
+		}
+	}
+	resetStats(&pokemon)
+	return pokemon, nil
+}
+
+func trainerSide(trainer Trainer) (*battleSide, error) {
+	side := &battleSide{name: trainer.Title}
+	for _, member := range trainer.Team {
+		pokemon, err := newPokemon(member.Species, member.Level, member.Moves)
+		if err != nil {
+			return nil, err
+		}
+		side.members = append(side.members, &battler{pokemon: pokemon})
+	}
+	return side, nil
+}
+
+func printTrainers() error {
+	trainers, err := loadTrainers()
+	if err != nil {
+		return err
+	}
+	fmt.Println(orange("Trainers you can challenge:"))
+	for _, trainer := range trainers {
+		status := ""
+		if DefeatedTrainers[trainer.Name] {
+			status = boldGreen(" (defeated)")
+		}
+		fmt.Printf("- %s: %s with %d pokemon%s\n", blue(trainer.Name), trainer.Title, len(trainer.Team), status)
+	}
+	progression, err := loadProgression()
+	if err != nil {
+		return err
+	}
+	fmt.Println(orange("Gym leaders:"))
+	for _, gym := range progression.Gyms {
+		status := ""
+		if hasBadge(gym.Badge) {
+			status = boldGreen(" (defeated)")
+		}
+		fmt.Printf("- %s: %s of the %s (%s type)%s\n", blue(gym.Leader.Name), gym.Leader.Title, gym.Name, gym.Type, status)
+	}
+	return nil
+}
+
+// challengeTrainer battles a trainer and pays out the prize money the first time they are beaten.
+func challengeTrainer(trainer Trainer) (bool, error) {
+	if len(Party) < 1 {
+		fmt.Printf("You have no pokemon in your party to fight with\nGo catch some pokemon!\n")
+		return false, nil
+	}
+	side, err := trainerSide(trainer)
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("%s wants to battle!\n", boldYellow(trainer.Title))
+	won, err := runBattle(side, &trainer, 1)
+	if err != nil || !won {
+		return won, err
+	}
+	if DefeatedTrainers[trainer.Name] {
+		fmt.Printf("%s was defeated again\n", trainer.Title)
+		return true, nil
+	}
+	if DefeatedTrainers == nil {
+		DefeatedTrainers = make(map[string]bool)
+	}
+	DefeatedTrainers[trainer.Name] = true
+	Money += trainer.Prize
+	fmt.Printf("You got %s for winning! You now have %s\n", boldGreen(fmt.Sprintf("₽%d", trainer.Prize)), boldYellow(fmt.Sprintf("₽%d", Money)))
+	return true, nil
+}
+
+func commandChallenge(_ *Config, trainerName string) error {
+	if trainerName == "" {
+		return printTrainers()
+	}
+	trainer, ok, err := findTrainer(trainerName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		gym, isGym, err := findGym(trainerName)
+		if err != nil {
+			return err
+		}
+		if isGym {
+			return challengeGym(gym)
+		}
+		fmt.Printf("There is no trainer called %s\n", trainerName)
+		return printTrainers()
+	}
+	_, err = challengeTrainer(trainer)
+	return err
+}
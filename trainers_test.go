@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Thijs-Desjardijn/pokedex/internal/pokecache"
+)
+
+func checkTeam(t *testing.T, trainer Trainer) {
+	if trainer.Name == "" || trainer.Title == "" {
+		t.Errorf("trainer %q needs a name and a title", trainer.Name)
+	}
+	if len(trainer.Team) == 0 || len(trainer.Team) > maxPartySize {
+		t.Errorf("%s has %d pokemon, expected 1 to %d", trainer.Name, len(trainer.Team), maxPartySize)
+	}
+	for _, member := range trainer.Team {
+		if member.Level < 1 || member.Level > maxLevel {
+			t.Errorf("%s has %s at level %d", trainer.Name, member.Species, member.Level)
+		}
+	}
+}
+
+func TestTrainersData(t *testing.T) {
+	trainers, err := loadTrainers()
+	if err != nil {
+		t.Fatalf("unable to load trainers: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, trainer := range trainers {
+		if seen[trainer.Name] {
+			t.Errorf("trainer %s is defined twice", trainer.Name)
+		}
+		seen[trainer.Name] = true
+		checkTeam(t, trainer)
+	}
+}
+
+// useTestCache swaps in an empty cache, the fake responses stored in it keep tests off the network.
+func useTestCache(t *testing.T) {
+	previous := cache
+	cache = pokecache.NewCache(time.Minute)
+	t.Cleanup(func() { cache = previous })
+}
+
+// cachePokemon stores a fake /pokemon response with the types and the moves it can learn.
+func cachePokemon(name string, types []string, learnset ...string) {
+	typeList := []string{}
+	for _, t := range types {
+		typeList = append(typeList, fmt.Sprintf(`{"type": {"name": %q}}`, t))
+	}
+	moveList := []string{}
+	for _, move := range learnset {
+		moveList = append(moveList, fmt.Sprintf(`{"move": {"name": %q, "url": "https://pokeapi.co/api/v2/move/%s"}}`, move, move))
+	}
+	stats := `[{"base_stat": 40, "stat": {"name": "hp"}}, {"base_stat": 45, "stat": {"name": "attack"}}, {"base_stat": 40, "stat": {"name": "defense"}},
+		{"base_stat": 35, "stat": {"name": "special-attack"}}, {"base_stat": 35, "stat": {"name": "special-defense"}}, {"base_stat": 56, "stat": {"name": "speed"}}]`
+	cache.Add("https://pokeapi.co/api/v2/pokemon/"+name, []byte(fmt.Sprintf(`{"name": %q, "stats": %s, "types": [%s], "moves": [%s], "abilities": [{"slot": 1, "ability": {"name": "run-away"}}]}`,
+		name, stats, strings.Join(typeList, ", "), strings.Join(moveList, ", "))))
+}
+
+func cacheMove(name, moveType, damageClass string, power int) {
+	cache.Add("https://pokeapi.co/api/v2/move/"+name, []byte(fmt.Sprintf(`{"name": %q, "power": %d, "type": {"name": %q}, "damage_class": {"name": %q}}`, name, power, moveType, damageClass)))
+}
+
+func TestTrainerBattleUsesEmbeddedTeam(t *testing.T) {
+	useTestCache(t)
+	previousDifficulty := Difficulty
+	Difficulty = "normal"
+	defer func() { Difficulty = previousDifficulty }()
+	cachePokemon("pidgey", []string{"normal", "flying"})
+	cachePokemon("nidoran-f", []string{"poison"})
+	cacheMove("tackle", "normal", "physical", 40)
+	cacheMove("gust", "flying", "special", 40)
+	cacheMove("scratch", "normal", "physical", 40)
+	cacheMove("double-kick", "fighting", "physical", 30)
+
+	janice, ok, err := findTrainer("janice")
+	if err != nil || !ok {
+		t.Fatalf("expected janice in the trainers data, got %v", err)
+	}
+	side, err := trainerSide(janice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(side.members) != len(janice.Team) {
+		t.Fatalf("expected %d pokemon, got %d", len(janice.Team), len(side.members))
+	}
+	for i, member := range janice.Team {
+		pokemon := side.members[i].pokemon
+		moves := []string{}
+		for _, move := range movesOf(pokemon) {
+			moves = append(moves, move.Name)
+		}
+		expected := slices.Sorted(slices.Values(member.Moves))
+		if pokemon.Name != member.Species || pokemon.Level != member.Level || !slices.Equal(moves, expected) {
+			t.Errorf("expected %s level %d with %v, got %s level %d with %v", member.Species, member.Level, expected, pokemon.Name, pokemon.Level, moves)
+		}
+	}
+
+	// the greedy AI of normal difficulty picks gust against a grass type
+	player := &battleSide{name: "you", members: []*battler{{pokemon: withMoves(testPokemon(5, 20, 20, "grass"), 20)}}}
+	player.sendOut(1)
+	side.sendOut(1)
+	b := &battle{player: player, opponent: side, slots: 1}
+	action, err := computerOpponent{}.chooseAction(b, side, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.actor != side.members[0] || action.move.Name != "gust" || action.target != player.members[0] {
+		t.Errorf("expected pidgey to use gust on the player, got %s using %s", action.actor.pokemon.Name, action.move.Name)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentArea is the location area the player is in, empty until the first goto.
+var CurrentArea string
+
+type Location struct {
+	Name   string             `json:"name"`
+	Region NamedAPIResource   `json:"region"`
+	Areas  []NamedAPIResource `json:"areas"`
+}
+
+func getLocationArea(name string) (LocationArea, error) {
+	var area LocationArea
+	err := getJSON("https://pokeapi.co/api/v2/location-area/"+name+"/", &area)
+	if err != nil {
+		return LocationArea{}, fmt.Errorf("unknown area %s, use map to list them", name)
+	}
+	return area, nil
+}
+
+// regionOf returns the region the location area lies in.
+func regionOf(area LocationArea) (string, error) {
+	var location Location
+	err := getJSON(area.Location.URL, &location)
+	if err != nil {
+		return "", err
+	}
+	return location.Region.Name, nil
+}
+
+// canTravel reports why the player can't go from one region to another. PokeAPI has no routes between
+// locations so every area of the current region is in reach, other regions have to border the current
+// one in the progression data. Every region needs its badges, also on the first trip.
+func canTravel(from, to string) (bool, string) {
+	if to == "" {
+		return false, "this area does not belong to a region you can travel to"
+	}
+	if from != "" && from != to && !regionsAdjacent(from, to) {
+		return false, fmt.Sprintf("%s can't be reached from %s, it only borders %s", to, from, strings.Join(regionNeighbours(from), " and "))
+	}
+	if !regionUnlocked(to) {
+		return false, fmt.Sprintf("you need more badges to travel to %s, see the badges command", to)
+	}
+	return true, ""
+}
+
+// areaOrCurrent fills in the current area when the player did not name one.
+func areaOrCurrent(area string) (string, error) {
+	if area != "" {
+		return area, nil
+	}
+	if CurrentArea == "" {
+		return "", fmt.Errorf("you are not in any area yet, use goto <area> first")
+	}
+	return CurrentArea, nil
+}
+
+// areaRegion returns the region of a location area by its name.
+func areaRegion(name string) (string, error) {
+	area, err := getLocationArea(name)
+	if err != nil {
+		return "", err
+	}
+	return regionOf(area)
+}
+
+func currentRegion() string {
+	if CurrentArea == "" {
+		return ""
+	}
+	region, err := areaRegion(CurrentArea)
+	if err != nil {
+		return ""
+	}
+	return region
+}
+
+func prompt() string {
+	if CurrentArea == "" {
+		return "Pokedex > "
+	}
+	return fmt.Sprintf("Pokedex (%s) > ", CurrentArea)
+}
+
+func commandGoto(_ *Config, name string) error {
+	if name == "" {
+		if CurrentArea == "" {
+			fmt.Println("You have not gone anywhere yet, use goto <area>")
+		} else {
+			fmt.Printf("You are at %s\n", orange(CurrentArea))
+		}
+		return nil
+	}
+	if name == CurrentArea {
+		fmt.Printf("You are already at %s\n", orange(name))
+		return nil
+	}
+	area, err := getLocationArea(name)
+	if err != nil {
+		return err
+	}
+	region, err := regionOf(area)
+	if err != nil {
+		return err
+	}
+	if ok, reason := canTravel(currentRegion(), region); !ok {
+		fmt.Println(reason)
+		return nil
+	}
+	CurrentArea = name
+	fmt.Printf("You travelled to %s in %s\n", orange(name), yellow(region))
+	return nil
+}
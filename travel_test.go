@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCanTravel(t *testing.T) {
+	allBadges := []string{}
+	for i := 0; i < 16; i++ {
+		allBadges = append(allBadges, "badge")
+	}
+	cases := []struct {
+		name     string
+		from     string
+		to       string
+		badges   []string
+		expected bool
+	}{
+		{name: "first trip", from: "", to: "kanto", expected: true},
+		{name: "first trip into a locked region", from: "", to: "paldea", expected: false},
+		{name: "first trip into an unlocked region", from: "", to: "johto", badges: []string{"boulder-badge", "cascade-badge", "thunder-badge", "rainbow-badge"}, expected: true},
+		{name: "within the region", from: "kanto", to: "kanto", expected: true},
+		{name: "neighbour without badges", from: "kanto", to: "johto", expected: false},
+		{name: "neighbour with badges", from: "kanto", to: "johto", badges: []string{"boulder-badge", "cascade-badge", "thunder-badge", "rainbow-badge"}, expected: true},
+		{name: "neighbour going back", from: "johto", to: "kanto", expected: true},
+		{name: "not a neighbour", from: "kanto", to: "hoenn", badges: allBadges, expected: false},
+		{name: "far away region", from: "kanto", to: "paldea", badges: allBadges, expected: false},
+		{name: "unknown region", from: "kanto", to: "", badges: allBadges, expected: false},
+	}
+	defer func() { Badges = nil }()
+	for _, c := range cases {
+		Badges = c.badges
+		actual, reason := canTravel(c.from, c.to)
+		if actual != c.expected {
+			t.Errorf("%s: expected %v, got %v (%s)", c.name, c.expected, actual, reason)
+		}
+	}
+}
+
+func TestRegionsAdjacent(t *testing.T) {
+	progression, err := loadProgression()
+	if err != nil {
+		t.Fatalf("unable to load regions: %v", err)
+	}
+	for _, access := range progression.Regions {
+		for _, neighbour := range access.Neighbours {
+			if !regionsAdjacent(neighbour, access.Name) {
+				t.Errorf("expected %s to border %s both ways", neighbour, access.Name)
+			}
+		}
+	}
+}
+
+func TestAreaOrCurrent(t *testing.T) {
+	defer func() { CurrentArea = "" }()
+	cases := []struct {
+		name           string
+		current        string
+		area           string
+		expected       string
+		expectedPrompt string
+		expectedError  bool
+	}{
+		{name: "before the first goto", current: "", area: "", expectedPrompt: "Pokedex > ", expectedError: true},
+		{name: "current area", current: "viridian-forest-area", area: "", expected: "viridian-forest-area", expectedPrompt: "Pokedex (viridian-forest-area) > "},
+		{name: "named area", current: "viridian-forest-area", area: "route-1", expected: "route-1", expectedPrompt: "Pokedex (viridian-forest-area) > "},
+	}
+	for _, c := range cases {
+		CurrentArea = c.current
+		actual, err := areaOrCurrent(c.area)
+		if (err != nil) != c.expectedError || actual != c.expected {
+			t.Errorf("%s: expected %q, got %q with error %v", c.name, c.expected, actual, err)
+		}
+		if prompt() != c.expectedPrompt {
+			t.Errorf("%s: expected prompt %q, got %q", c.name, c.expectedPrompt, prompt())
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ShinyRate is the one in how many encounters that is shiny.
+var ShinyRate = 4096
+
+// formRate is the one in how many encounters that shows an alternate form, when the species has one.
+const formRate = 8
+
+// DexVariants tracks caught forms, shinies and genders, see variantKeys.
+var DexVariants map[string]bool
+
+type SpeciesVariety struct {
+	IsDefault bool             `json:"is_default"`
+	Pokemon   NamedAPIResource `json:"pokemon"`
+}
+
+// wildForm reports whether a variety can be met in the wild, mega evolutions and the like only exist in battle.
+func wildForm(name string) bool {
+	if strings.Contains(name, "-totem") {
+		return false
+	}
+	for _, suffix := range []string{"-mega", "-mega-x", "-mega-y", "-gmax", "-primal", "-eternamax"} {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// chooseForm returns the pokemon to encounter for the species, usually its default form.
+func chooseForm(species PokemonSpecies, name string, intn func(int) int) string {
+	forms := []string{}
+	for _, variety := range species.Varieties {
+		if !variety.IsDefault && wildForm(variety.Pokemon.Name) {
+			forms = append(forms, variety.Pokemon.Name)
+		}
+	}
+	if len(forms) == 0 || intn(formRate) != 0 {
+		return name
+	}
+	return forms[intn(len(forms))]
+}
+
+// chooseGender uses the species gender_rate, the chance in eighths of being female.
+func chooseGender(species PokemonSpecies, intn func(int) int) string {
+	if species.GenderRate < 0 {
+		return "genderless"
+	}
+	if intn(8) < species.GenderRate {
+		return "female"
+	}
+	return "male"
+}
+
+func rollShiny(intn func(int) int) bool {
+	return intn(max(ShinyRate, 1)) == 0
+}
+
+// encounter picks the form, gender and shininess of a wild pokemon of the named kind.
+// The name is a /pokemon name, which differs from the species for pokemon like basculin-red-striped.
+func encounter(name string, intn func(int) int) (PokemonInformation, error) {
+	var pokemon PokemonInformation
+	err := getJSON("https://pokeapi.co/api/v2/pokemon/"+name, &pokemon)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	species, err := getSpecies(pokemon)
+	if err != nil {
+		return PokemonInformation{}, err
+	}
+	if form := chooseForm(species, name, intn); form != name {
+		pokemon = PokemonInformation{}
+		err = getJSON("https://pokeapi.co/api/v2/pokemon/"+form, &pokemon)
+		if err != nil {
+			return PokemonInformation{}, err
+		}
+	}
+	pokemon.Gender = chooseGender(species, intn)
+	pokemon.Shiny = rollShiny(intn)
+	return pokemon, nil
+}
+
+// variantKeys are the DexVariants entries a caught pokemon fills in.
+func variantKeys(pokemon PokemonInformation) []string {
+	species := speciesName(pokemon)
+	keys := []string{}
+	if pokemon.Name != species {
+		keys = append(keys, "form:"+pokemon.Name)
+	}
+	if pokemon.Shiny {
+		keys = append(keys, "shiny:"+species)
+	}
+	if pokemon.Gender == "male" || pokemon.Gender == "female" {
+		keys = append(keys, "gender:"+species+"-"+pokemon.Gender)
+	}
+	return keys
+}
+
+func markVariants(pokemon PokemonInformation) {
+	if DexVariants == nil {
+		DexVariants = make(map[string]bool)
+	}
+	for _, key := range variantKeys(pokemon) {
+		DexVariants[key] = true
+	}
+}
+
+// countVariants counts the caught variants per kind, like "shiny" or "form".
+func countVariants() map[string]int {
+	counts := map[string]int{}
+	for key := range DexVariants {
+		kind, _, _ := strings.Cut(key, ":")
+		counts[kind]++
+	}
+	return counts
+}
+
+// variantsOf lists the variants caught of one species.
+func variantsOf(species string) []string {
+	found := []string{}
+	for key := range DexVariants {
+		kind, name, _ := strings.Cut(key, ":")
+		switch {
+		case kind == "shiny" && name == species:
+			found = append(found, "shiny")
+		case kind == "gender" && strings.HasPrefix(name, species+"-"):
+			found = append(found, strings.TrimPrefix(name, species+"-"))
+		case kind == "form" && strings.HasPrefix(name, species+"-"):
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// variantTag marks shiny pokemon and their gender in lists.
+func variantTag(pokemon PokemonInformation) string {
+	tag := ""
+	switch pokemon.Gender {
+	case "male":
+		tag += " " + blue("♂")
+	case "female":
+		tag += " " + red("♀")
+	}
+	if pokemon.Shiny {
+		tag += " " + boldYellow("★")
+	}
+	return tag
+}
+
+func commandShiny(_ *Config, rate string) error {
+	if rate == "" {
+		fmt.Printf("Shiny rate: 1 in %s\n", yellow(ShinyRate))
+		return nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(rate, "1/"))
+	if err != nil || n < 1 {
+		return fmt.Errorf("usage: shiny <n>, to make 1 in n encounters shiny")
+	}
+	ShinyRate = n
+	fmt.Printf("Shiny rate set to 1 in %s\n", yellow(ShinyRate))
+	return nil
+}
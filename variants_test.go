@@ -0,0 +1,109 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// fixed returns an intn that always rolls the given value.
+func fixed(roll int) func(int) int {
+	return func(int) int { return roll }
+}
+
+func TestChooseGender(t *testing.T) {
+	cases := []struct {
+		rate     int
+		roll     int
+		expected string
+	}{
+		{rate: -1, roll: 0, expected: "genderless"},
+		{rate: 0, roll: 0, expected: "male"},
+		{rate: 1, roll: 0, expected: "female"},
+		{rate: 1, roll: 1, expected: "male"},
+		{rate: 8, roll: 7, expected: "female"},
+	}
+	for _, c := range cases {
+		actual := chooseGender(PokemonSpecies{GenderRate: c.rate}, fixed(c.roll))
+		if actual != c.expected {
+			t.Errorf("rate %d, roll %d: expected %s, got %s", c.rate, c.roll, c.expected, actual)
+		}
+	}
+}
+
+func TestChooseForm(t *testing.T) {
+	raticate := PokemonSpecies{Varieties: []SpeciesVariety{
+		{IsDefault: true, Pokemon: NamedAPIResource{Name: "raticate"}},
+		{Pokemon: NamedAPIResource{Name: "raticate-alola"}},
+		{Pokemon: NamedAPIResource{Name: "raticate-totem-alola"}},
+	}}
+	cases := []struct {
+		name     string
+		species  PokemonSpecies
+		pokemon  string
+		roll     int
+		expected string
+	}{
+		{name: "alternate form", species: raticate, pokemon: "raticate", roll: 0, expected: "raticate-alola"},
+		{name: "default form", species: raticate, pokemon: "raticate", roll: 1, expected: "raticate"},
+		{name: "no varieties", species: PokemonSpecies{}, pokemon: "pidgey", roll: 0, expected: "pidgey"},
+	}
+	for _, c := range cases {
+		actual := chooseForm(c.species, c.pokemon, fixed(c.roll))
+		if actual != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, actual)
+		}
+	}
+	for name, expected := range map[string]bool{"charizard-mega-x": false, "venusaur-gmax": false, "raticate-alola": true} {
+		if wildForm(name) != expected {
+			t.Errorf("%s: expected wild %v, got %v", name, expected, !expected)
+		}
+	}
+}
+
+func TestVariantKeys(t *testing.T) {
+	pokemon := PokemonInformation{Name: "vulpix-alola", Species: NamedAPIResource{Name: "vulpix"}, Gender: "female", Shiny: true}
+	expected := []string{"form:vulpix-alola", "shiny:vulpix", "gender:vulpix-female"}
+	if actual := variantKeys(pokemon); !slices.Equal(actual, expected) {
+		t.Errorf("expected keys %v, got %v", expected, actual)
+	}
+	DexVariants = nil
+	markVariants(pokemon)
+	markVariants(PokemonInformation{Name: "magnemite", Gender: "genderless"})
+	counts := countVariants()
+	if counts["form"] != 1 || counts["shiny"] != 1 || counts["gender"] != 1 {
+		t.Errorf("expected one variant of each kind, got %v", counts)
+	}
+	variants := variantsOf("vulpix")
+	slices.Sort(variants)
+	if !slices.Equal(variants, []string{"female", "shiny", "vulpix-alola"}) {
+		t.Errorf("expected female, shiny and vulpix-alola, got %v", variants)
+	}
+}
+
+func TestEncounterFormNamedPokemon(t *testing.T) {
+	useTestCache(t)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/basculin-red-striped", []byte(`{"name": "basculin-red-striped",
+		"species": {"name": "basculin", "url": "https://pokeapi.co/api/v2/pokemon-species/550/"}}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/basculin-blue-striped", []byte(`{"name": "basculin-blue-striped",
+		"species": {"name": "basculin", "url": "https://pokeapi.co/api/v2/pokemon-species/550/"}}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/550/", []byte(`{"name": "basculin", "gender_rate": 4, "varieties": [
+		{"is_default": true, "pokemon": {"name": "basculin-red-striped"}},
+		{"is_default": false, "pokemon": {"name": "basculin-blue-striped"}}
+	]}`))
+	cases := []struct {
+		roll     int
+		expected string
+	}{
+		{roll: 1, expected: "basculin-red-striped"},
+		{roll: 0, expected: "basculin-blue-striped"},
+	}
+	for _, c := range cases {
+		pokemon, err := encounter("basculin-red-striped", fixed(c.roll))
+		if err != nil {
+			t.Fatalf("roll %d: %v", c.roll, err)
+		}
+		if pokemon.Name != c.expected || speciesName(pokemon) != "basculin" {
+			t.Errorf("roll %d: expected %s of the species basculin, got %s of %s", c.roll, c.expected, pokemon.Name, speciesName(pokemon))
+		}
+	}
+}